@@ -84,8 +84,14 @@ func (a *API) ListThreads(chatID, sortOrder, pageID string, limit, minEventsCoun
 }
 
 // ListArchives returns archived chats.
+// Filters are validated before sending.
 func (a *API) ListArchives(filters *archivesFilters, page, limit uint) (chats []objects.Chat, currentPage, totalPages uint, err error) {
 	var resp listArchivesResponse
+	if filters != nil {
+		if err := filters.Validate(); err != nil {
+			return nil, 0, 0, err
+		}
+	}
 	err = a.Call("list_archives", &listArchivesRequest{
 		Filters: filters,
 		Pagination: &paginationRequest{
@@ -216,7 +222,8 @@ func (a *API) RemoveUserFromChat(chatID, userID, userType string) error {
 // SendEvent sends event of supported type to given chat.
 // It returns event ID.
 //
-// Supported event types are: event, message, system_message and file.
+// Supported event types are: event, custom, file, filled_form, message, rich_message and system_message.
+// Events are validated with objects.ValidateEvent before sending.
 func (a *API) SendEvent(chatID string, event interface{}, attachToLastThread bool) (string, error) {
 	if err := objects.ValidateEvent(event); err != nil {
 		return "", err
@@ -328,8 +335,14 @@ func (a *API) GetCustomer(customerID string) (customer objects.Customer, err err
 }
 
 // ListCustomers returns the list of Customers.
+// Filters are validated before sending.
 func (a *API) ListCustomers(limit uint, pageID, sortOrder string, filters *customersFilters) (customers []objects.Customer, total uint, previousPage, nextPage string, err error) {
 	var resp listCustomersResponse
+	if filters != nil {
+		if err := filters.Validate(); err != nil {
+			return nil, 0, "", "", err
+		}
+	}
 	err = a.Call("list_customers", &listCustomersRequest{
 		PageID:    pageID,
 		Limit:     limit,
@@ -341,8 +354,12 @@ func (a *API) ListCustomers(limit uint, pageID, sortOrder string, filters *custo
 }
 
 // CreateCustomer creates new Customer.
-func (a *API) CreateCustomer(name, email, avatar string, sessionFields []map[string]string) (string, error) {
+// Session fields are validated before sending.
+func (a *API) CreateCustomer(name, email, avatar string, sessionFields objects.SessionFields) (string, error) {
 	var resp createCustomerResponse
+	if err := sessionFields.Validate(); err != nil {
+		return "", err
+	}
 	err := a.Call("create_customer", &createCustomerRequest{
 		Name:          name,
 		Email:         email,
@@ -354,7 +371,11 @@ func (a *API) CreateCustomer(name, email, avatar string, sessionFields []map[str
 }
 
 // UpdateCustomer updates customer's info.
-func (a *API) UpdateCustomer(customerID, name, email, avatar string, sessionFields []map[string]string) error {
+// Session fields are validated before sending.
+func (a *API) UpdateCustomer(customerID, name, email, avatar string, sessionFields objects.SessionFields) error {
+	if err := sessionFields.Validate(); err != nil {
+		return err
+	}
 	return a.Call("update_customer", &updateCustomerRequest{
 		CustomerID:    customerID,
 		Name:          name,
@@ -375,7 +396,10 @@ func (a *API) BanCustomer(customerID string, days uint) error {
 }
 
 // SetRoutingStatus changes status of an agent or a bot.
-func (a *API) SetRoutingStatus(agentID, status string) error {
+func (a *API) SetRoutingStatus(agentID string, status objects.RoutingStatus) error {
+	if err := status.Validate(); err != nil {
+		return err
+	}
 	return a.Call("set_routing_status", &setRoutingStatusRequest{
 		AgentID: agentID,
 		Status:  status,
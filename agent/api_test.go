@@ -969,12 +969,28 @@ func TestSetRoutingStatusShouldReturnDataReceivedFromAgentAPI(t *testing.T) {
 		t.Errorf("API creation failed")
 	}
 
-	rErr := api.SetRoutingStatus("some_agent", "accepting chats")
+	rErr := api.SetRoutingStatus("some_agent", objects.AcceptingChats)
 	if rErr != nil {
 		t.Errorf("SetRoutingStatus failed: %v", rErr)
 	}
 }
 
+func TestSetRoutingStatusShouldRejectInvalidStatus(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		t.Errorf("request should not be sent")
+		return nil
+	})
+
+	api, err := agent.NewAPI(stubBearerTokenGetter, client, "client_id")
+	if err != nil {
+		t.Errorf("API creation failed")
+	}
+
+	if rErr := api.SetRoutingStatus("some_agent", "accepting chats"); rErr == nil {
+		t.Errorf("SetRoutingStatus should fail")
+	}
+}
+
 func TestMarkEventsAsSeenShouldReturnDataReceivedFromAgentAPI(t *testing.T) {
 	client := NewTestClient(createMockedResponder(t, "mark_events_as_seen"))
 
@@ -1107,6 +1123,41 @@ func TestListThreadsShouldNotCrashOnErrorResponse(t *testing.T) {
 	verifyErrorResponse("ListThreads", rErr, t)
 }
 
+func TestListArchivesShouldRejectContradictoryFilters(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		t.Errorf("request should not be sent")
+		return nil
+	})
+
+	api, err := agent.NewAPI(stubBearerTokenGetter, client, "client_id")
+	if err != nil {
+		t.Errorf("API creation failed")
+	}
+
+	now := time.Now()
+	filters := agent.NewArchivesFilters().FromTime(now).ToTime(now.Add(-time.Hour))
+	if _, _, _, rErr := api.ListArchives(filters, 1, 20); rErr == nil {
+		t.Errorf("ListArchives should fail")
+	}
+}
+
+func TestUpdateCustomerShouldRejectDuplicatedSessionFields(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		t.Errorf("request should not be sent")
+		return nil
+	})
+
+	api, err := agent.NewAPI(stubBearerTokenGetter, client, "client_id")
+	if err != nil {
+		t.Errorf("API creation failed")
+	}
+
+	fields := objects.SessionFields{{"plan": "pro"}, {"plan": "free"}}
+	if rErr := api.UpdateCustomer("b7eff798-f8df-4364-8059-649c35c9ed0c", "", "", "", fields); rErr == nil {
+		t.Errorf("UpdateCustomer should fail")
+	}
+}
+
 func TestListArchivesShouldNotCrashOnErrorResponse(t *testing.T) {
 	client := NewTestClient(createMockedErrorResponder(t, "list_archives"))
 
@@ -1392,7 +1443,7 @@ func TestSetRoutingStatusShouldNotCrashOnErrorResponse(t *testing.T) {
 		t.Errorf("API creation failed")
 	}
 
-	rErr := api.SetRoutingStatus("some_agent", "accepting chats")
+	rErr := api.SetRoutingStatus("some_agent", objects.AcceptingChats)
 	verifyErrorResponse("SetRoutingStatus", rErr, t)
 }
 
@@ -0,0 +1,344 @@
+package agent
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/livechat/lc-sdk-go/v2/objects"
+	"github.com/livechat/lc-sdk-go/v2/properties"
+)
+
+// DuplicateKey identifies customers that are likely duplicates of each other.
+type DuplicateKey struct {
+	Name string
+	// Value returns normalized value shared by duplicated customers or empty string if customer has no such value.
+	Value func(objects.Customer) string
+}
+
+// Built-in duplicate keys.
+var (
+	// EmailKey matches customers by case insensitive email, ignoring "+suffix" in its local part.
+	EmailKey = DuplicateKey{Name: "email", Value: func(c objects.Customer) string {
+		return normalizeEmail(c.Email)
+	}}
+	// NameKey matches customers by case insensitive name, ignoring differences in whitespace.
+	NameKey = DuplicateKey{Name: "name", Value: func(c objects.Customer) string {
+		return strings.ToLower(strings.Join(strings.Fields(c.Name), " "))
+	}}
+)
+
+// SessionFieldKey matches customers by case insensitive value of given session field.
+func SessionFieldKey(field string) DuplicateKey {
+	return DuplicateKey{Name: "session." + field, Value: func(c objects.Customer) string {
+		for _, sf := range c.SessionFields {
+			if v, exists := sf[field]; exists {
+				return strings.ToLower(strings.TrimSpace(v))
+			}
+		}
+		return ""
+	}}
+}
+
+func normalizeEmail(email string) string {
+	email = strings.ToLower(strings.TrimSpace(email))
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return email
+	}
+	local, domain := email[:at], email[at:]
+	if plus := strings.Index(local, "+"); plus > 0 {
+		local = local[:plus]
+	}
+	return local + domain
+}
+
+// DuplicateGroup represents customers considered duplicates of each other.
+type DuplicateGroup struct {
+	// Reasons lists keys shared by customers in group, ie. "email=jane@example.com".
+	Reasons []string
+	// Customers are sorted by creation time, oldest first.
+	Customers []objects.Customer
+}
+
+// Primary returns the oldest customer in group, the one duplicates are consolidated into.
+func (g DuplicateGroup) Primary() objects.Customer {
+	return g.Customers[0]
+}
+
+// Duplicates returns all customers in group except the primary one.
+func (g DuplicateGroup) Duplicates() []objects.Customer {
+	return g.Customers[1:]
+}
+
+// DuplicatesReport summarizes customers scan.
+type DuplicatesReport struct {
+	Scanned int
+	Groups  []DuplicateGroup
+}
+
+// String returns human readable representation of report.
+func (r *DuplicatesReport) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d customers scanned, %d duplicate groups found\n", r.Scanned, len(r.Groups))
+	for _, g := range r.Groups {
+		fmt.Fprintf(&b, "%s:\n", strings.Join(g.Reasons, ", "))
+		for i, c := range g.Customers {
+			marker := " "
+			if i == 0 {
+				marker = "*"
+			}
+			fmt.Fprintf(&b, " %s %s %q <%s> created %s\n", marker, c.ID, c.Name, c.Email, c.CreatedAt.Format(DateFormat))
+		}
+	}
+	return b.String()
+}
+
+// Consolidation describes changes made by Deduplicator.Consolidate.
+type Consolidation struct {
+	PrimaryID string
+	// SessionFields are session fields of primary customer after consolidation.
+	SessionFields objects.SessionFields
+	// TaggedChats lists IDs of duplicates' chats marked with DuplicateOfProperty.
+	TaggedChats []string
+	// Truncated is true if scanning chats stopped at the page limit (see WithMaxChatPages) while more chats
+	// were available, in which case some of duplicates' chats may not be tagged.
+	Truncated bool
+}
+
+// DuplicateOfProperty is the chat property storing ID of the primary customer in chats of its duplicates.
+const DuplicateOfProperty = "duplicate_of"
+
+const (
+	dedupCustomersPageLimit = 100
+	// DefaultDedupMaxChatPages is default number of pages of chats scanned in search for duplicates' chats.
+	DefaultDedupMaxChatPages = 20
+)
+
+// Deduplicator finds duplicated customers and consolidates them.
+//
+// Customers API doesn't allow merging customers, so consolidation copies missing data into the primary
+// customer and marks chats of duplicates with DuplicateOfProperty pointing at it.
+type Deduplicator struct {
+	api          *API
+	filters      *customersFilters
+	chatsFilters *chatsFilters
+	keys         []DuplicateKey
+	maxChatPages uint
+	schema       *properties.Schema
+}
+
+// NewDeduplicator creates Deduplicator matching customers by EmailKey. DuplicateOfProperty is stored in given
+// namespace, which has to be registered (see Schema) before consolidating.
+func NewDeduplicator(api *API, namespace string) *Deduplicator {
+	schema := properties.NewSchema(namespace)
+	schema.DefineString(DuplicateOfProperty, "").
+		WithDescription("ID of the customer this chat's customer is a duplicate of").
+		WithAccess("chat", "agent", true, true)
+
+	return &Deduplicator{
+		api:          api,
+		chatsFilters: NewChatsFilters(),
+		keys:         []DuplicateKey{EmailKey},
+		maxChatPages: DefaultDedupMaxChatPages,
+		schema:       schema,
+	}
+}
+
+// WithCustomersFilters limits scanned customers to those matching given filters.
+func (d *Deduplicator) WithCustomersFilters(filters *customersFilters) *Deduplicator {
+	d.filters = filters
+	return d
+}
+
+// WithChatsFilters sets filters used to list chats of duplicated customers.
+func (d *Deduplicator) WithChatsFilters(filters *chatsFilters) *Deduplicator {
+	d.chatsFilters = filters
+	return d
+}
+
+// WithKeys sets keys used to match duplicates. Customers sharing value of any key are grouped together.
+func (d *Deduplicator) WithKeys(keys ...DuplicateKey) *Deduplicator {
+	d.keys = keys
+	return d
+}
+
+// WithMaxChatPages sets maximum number of pages of chats scanned in search for duplicates' chats.
+func (d *Deduplicator) WithMaxChatPages(maxPages uint) *Deduplicator {
+	d.maxChatPages = maxPages
+	return d
+}
+
+// Schema returns schema of properties used by Deduplicator.
+func (d *Deduplicator) Schema() *properties.Schema {
+	return d.schema
+}
+
+// Scan lists all customers matching filters and groups duplicates.
+func (d *Deduplicator) Scan() (*DuplicatesReport, error) {
+	var customers []objects.Customer
+	pageID := ""
+	for {
+		page, _, _, nextPage, err := d.api.ListCustomers(dedupCustomersPageLimit, pageID, "asc", d.filters)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't list customers: %v", err)
+		}
+		customers = append(customers, page...)
+		if nextPage == "" {
+			break
+		}
+		pageID = nextPage
+	}
+
+	return &DuplicatesReport{
+		Scanned: len(customers),
+		Groups:  groupDuplicates(customers, d.keys),
+	}, nil
+}
+
+func groupDuplicates(customers []objects.Customer, keys []DuplicateKey) []DuplicateGroup {
+	parent := make([]int, len(customers))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	values := make([][]string, len(customers))
+	for i, c := range customers {
+		values[i] = make([]string, len(keys))
+		for k, key := range keys {
+			values[i][k] = key.Value(c)
+		}
+	}
+	for k := range keys {
+		first := make(map[string]int)
+		for i := range customers {
+			v := values[i][k]
+			if v == "" {
+				continue
+			}
+			if j, seen := first[v]; seen {
+				parent[find(i)] = find(j)
+			} else {
+				first[v] = i
+			}
+		}
+	}
+
+	members := make(map[int][]int)
+	for i := range customers {
+		root := find(i)
+		members[root] = append(members[root], i)
+	}
+
+	var groups []DuplicateGroup
+	for _, indices := range members {
+		if len(indices) < 2 {
+			continue
+		}
+		group := DuplicateGroup{}
+		for k, key := range keys {
+			counts := make(map[string]int)
+			var order []string
+			for _, i := range indices {
+				if v := values[i][k]; v != "" {
+					if counts[v] == 0 {
+						order = append(order, v)
+					}
+					counts[v]++
+				}
+			}
+			for _, v := range order {
+				if counts[v] > 1 {
+					group.Reasons = append(group.Reasons, key.Name+"="+v)
+				}
+			}
+		}
+		for _, i := range indices {
+			group.Customers = append(group.Customers, customers[i])
+		}
+		sort.SliceStable(group.Customers, func(i, j int) bool {
+			return customerBefore(group.Customers[i], group.Customers[j])
+		})
+		groups = append(groups, group)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return customerBefore(groups[i].Primary(), groups[j].Primary())
+	})
+	return groups
+}
+
+func customerBefore(a, b objects.Customer) bool {
+	if !a.CreatedAt.Equal(b.CreatedAt) {
+		return a.CreatedAt.Before(b.CreatedAt)
+	}
+	return a.ID < b.ID
+}
+
+// Consolidate copies session fields missing in primary customer of group (and its name or email if empty) from
+// duplicates and marks duplicates' chats with DuplicateOfProperty.
+func (d *Deduplicator) Consolidate(group DuplicateGroup) (*Consolidation, error) {
+	if len(group.Customers) < 2 {
+		return nil, fmt.Errorf("group has to contain at least 2 customers")
+	}
+	primary := group.Primary()
+	result := &Consolidation{PrimaryID: primary.ID}
+
+	name, email := "", ""
+	fields := primary.SessionFields.Clone()
+	cloned := fields.Len()
+	var duplicateIDs []string
+	for _, c := range group.Duplicates() {
+		duplicateIDs = append(duplicateIDs, c.ID)
+		if primary.Name == "" && name == "" {
+			name = c.Name
+		}
+		if primary.Email == "" && email == "" {
+			email = c.Email
+		}
+		fields.Merge(c.SessionFields, false)
+	}
+	result.SessionFields = fields
+
+	if name != "" || email != "" || fields.Len() > cloned {
+		if err := d.api.UpdateCustomer(primary.ID, name, email, "", fields); err != nil {
+			return nil, fmt.Errorf("couldn't update customer %s: %v", primary.ID, err)
+		}
+	}
+
+	chats, truncated, err := d.api.customersChats(d.chatsFilters, duplicateIDs, math.MaxUint32, d.maxChatPages)
+	if err != nil {
+		return result, fmt.Errorf("couldn't list duplicates' chats: %v", err)
+	}
+	result.Truncated = truncated
+	var errs []string
+	tagged := make(map[string]bool)
+	for _, id := range duplicateIDs {
+		for _, chat := range chats[id] {
+			if tagged[chat.ID] {
+				continue
+			}
+			tagged[chat.ID] = true
+			props := objects.Properties{}
+			if err := d.schema.Set(props, DuplicateOfProperty, primary.ID); err != nil {
+				return result, err
+			}
+			if err := d.api.UpdateChatProperties(chat.ID, props); err != nil {
+				errs = append(errs, fmt.Sprintf("%s: %v", chat.ID, err))
+				continue
+			}
+			result.TaggedChats = append(result.TaggedChats, chat.ID)
+		}
+	}
+	if len(errs) > 0 {
+		return result, fmt.Errorf("couldn't tag chats: %s", strings.Join(errs, "; "))
+	}
+	return result, nil
+}
@@ -0,0 +1,207 @@
+package agent_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/livechat/lc-sdk-go/v2/agent"
+	"github.com/livechat/lc-sdk-go/v2/objects"
+)
+
+var dedupResponses = map[string][]string{
+	"list_customers": {
+		`{
+			"customers": [
+				{"id": "c1", "type": "customer", "name": "Jane Doe", "email": "jane@example.com", "created_at": "2020-01-01T10:00:00.000000Z", "session_fields": [{"plan": "pro"}]},
+				{"id": "c2", "type": "customer", "name": "John", "email": "john@example.com", "created_at": "2020-01-02T10:00:00.000000Z"}
+			],
+			"total_customers": 4,
+			"next_page_id": "page2"
+		}`,
+		`{
+			"customers": [
+				{"id": "c3", "type": "customer", "name": "jane  doe", "email": "Jane+shop@Example.com", "created_at": "2019-12-01T10:00:00.000000Z", "session_fields": [{"plan": "free"}, {"crm_id": "42"}]},
+				{"id": "c4", "type": "customer", "email": "", "created_at": "2020-01-03T10:00:00.000000Z"}
+			],
+			"total_customers": 4
+		}`,
+	},
+	"list_chats": {
+		`{
+			"chats_summary": [
+				{"id": "CHAT1", "users": [{"id": "c1", "type": "customer"}]},
+				{"id": "CHAT2", "users": [{"id": "c2", "type": "customer"}]}
+			],
+			"found_chats": 2
+		}`,
+	},
+	"update_customer":        {`{}`},
+	"update_chat_properties": {`{}`},
+}
+
+func createDedupResponder(t *testing.T, requests map[string][]string) roundTripFunc {
+	return func(req *http.Request) *http.Response {
+		action := path.Base(req.URL.Path)
+		body, _ := ioutil.ReadAll(req.Body)
+		requests[action] = append(requests[action], string(body))
+
+		responses, exists := dedupResponses[action]
+		if !exists {
+			t.Errorf("unexpected action: %v", action)
+			responses = []string{`{}`}
+		}
+		i := len(requests[action]) - 1
+		if i >= len(responses) {
+			i = len(responses) - 1
+		}
+		return &http.Response{
+			StatusCode: 200,
+			Body:       ioutil.NopCloser(bytes.NewBufferString(responses[i])),
+			Header:     make(http.Header),
+		}
+	}
+}
+
+func TestDeduplicatorScan(t *testing.T) {
+	requests := make(map[string][]string)
+	api, err := agent.NewAPI(stubBearerTokenGetter, NewTestClient(createDedupResponder(t, requests)), "client_id")
+	if err != nil {
+		t.Errorf("API creation failed")
+	}
+
+	report, err := agent.NewDeduplicator(api, "client_id").WithKeys(agent.EmailKey, agent.NameKey).Scan()
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+
+	if len(requests["list_customers"]) != 2 || !strings.Contains(requests["list_customers"][1], `"page_id":"page2"`) {
+		t.Errorf("customers should be listed page by page: %v", requests["list_customers"])
+	}
+	if report.Scanned != 4 || len(report.Groups) != 1 {
+		t.Fatalf("invalid report: %v", report)
+	}
+	group := report.Groups[0]
+	if group.Primary().ID != "c3" || len(group.Duplicates()) != 1 || group.Duplicates()[0].ID != "c1" {
+		t.Errorf("invalid group: %+v", group)
+	}
+	if strings.Join(group.Reasons, ",") != "email=jane@example.com,name=jane doe" {
+		t.Errorf("invalid reasons: %v", group.Reasons)
+	}
+	if !strings.Contains(report.String(), "* c3") {
+		t.Errorf("primary customer should be marked in report: %v", report)
+	}
+}
+
+func TestSessionFieldKey(t *testing.T) {
+	key := agent.SessionFieldKey("crm_id")
+	c := objects.Customer{User: &objects.User{}, SessionFields: []map[string]string{{"plan": "pro"}, {"crm_id": " AB12 "}}}
+	if v := key.Value(c); v != "ab12" {
+		t.Errorf("invalid session field value: %v", v)
+	}
+	c.SessionFields = nil
+	if v := key.Value(c); v != "" {
+		t.Errorf("customer without session field should have empty value: %v", v)
+	}
+}
+
+func TestDeduplicatorConsolidate(t *testing.T) {
+	requests := make(map[string][]string)
+	api, err := agent.NewAPI(stubBearerTokenGetter, NewTestClient(createDedupResponder(t, requests)), "client_id")
+	if err != nil {
+		t.Errorf("API creation failed")
+	}
+
+	group := agent.DuplicateGroup{Customers: []objects.Customer{
+		{User: &objects.User{ID: "c2"}, SessionFields: []map[string]string{{"plan": "pro"}}},
+		{User: &objects.User{ID: "c1", Name: "Jane"}, SessionFields: []map[string]string{{"plan": "free"}, {"crm_id": "42"}}},
+	}}
+	dd := agent.NewDeduplicator(api, "dedup")
+	result, err := dd.Consolidate(group)
+	if err != nil {
+		t.Fatalf("Consolidate failed: %v", err)
+	}
+
+	if len(requests["update_customer"]) != 1 {
+		t.Fatalf("primary customer should be updated once: %v", requests)
+	}
+	var update struct {
+		CustomerID    string              `json:"customer_id"`
+		Name          string              `json:"name"`
+		SessionFields []map[string]string `json:"session_fields"`
+	}
+	if err := json.Unmarshal([]byte(requests["update_customer"][0]), &update); err != nil {
+		t.Fatalf("couldn't unmarshal request: %v", err)
+	}
+	if update.CustomerID != "c2" || update.Name != "Jane" || len(update.SessionFields) != 2 ||
+		update.SessionFields[0]["plan"] != "pro" || update.SessionFields[1]["crm_id"] != "42" {
+		t.Errorf("invalid update: %+v", update)
+	}
+
+	if len(result.TaggedChats) != 1 || result.TaggedChats[0] != "CHAT1" || result.Truncated {
+		t.Errorf("invalid tagged chats: %v, %v", result.TaggedChats, result.Truncated)
+	}
+	if len(requests["update_chat_properties"]) != 1 || !strings.Contains(requests["update_chat_properties"][0], `"dedup":{"duplicate_of":"c2"}`) {
+		t.Errorf("invalid chat properties update: %v", requests["update_chat_properties"])
+	}
+	if dd.Schema().Definition(agent.DuplicateOfProperty) == nil {
+		t.Errorf("schema should define %s property", agent.DuplicateOfProperty)
+	}
+}
+
+func TestDeduplicatorConsolidateShouldDropDuplicatedSessionFields(t *testing.T) {
+	requests := make(map[string][]string)
+	api, err := agent.NewAPI(stubBearerTokenGetter, NewTestClient(createDedupResponder(t, requests)), "client_id")
+	if err != nil {
+		t.Errorf("API creation failed")
+	}
+
+	group := agent.DuplicateGroup{Customers: []objects.Customer{
+		{User: &objects.User{ID: "c2", Name: "Jane"}, SessionFields: []map[string]string{{"plan": "pro"}, {"plan": "free"}}},
+		{User: &objects.User{ID: "c1"}, SessionFields: []map[string]string{{"crm_id": "42"}}},
+	}}
+	result, err := agent.NewDeduplicator(api, "dedup").Consolidate(group)
+	if err != nil {
+		t.Fatalf("Consolidate failed: %v", err)
+	}
+	if strings.Join(result.SessionFields.Keys(), ",") != "plan,crm_id" {
+		t.Errorf("invalid session fields: %v", result.SessionFields)
+	}
+	if len(requests["update_customer"]) != 1 || !strings.Contains(requests["update_customer"][0], `"session_fields":[{"plan":"pro"},{"crm_id":"42"}]`) {
+		t.Errorf("invalid update: %v", requests["update_customer"])
+	}
+}
+
+func TestDeduplicatorConsolidateShouldReportTruncatedChats(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		body := `{}`
+		if path.Base(req.URL.Path) == "list_chats" {
+			body = `{"chats_summary": [{"id": "CHAT1", "users": [{"id": "c1", "type": "customer"}]}], "found_chats": 2, "next_page_id": "page2"}`
+		}
+		return &http.Response{
+			StatusCode: 200,
+			Body:       ioutil.NopCloser(bytes.NewBufferString(body)),
+			Header:     make(http.Header),
+		}
+	})
+	api, err := agent.NewAPI(stubBearerTokenGetter, client, "client_id")
+	if err != nil {
+		t.Errorf("API creation failed")
+	}
+
+	group := agent.DuplicateGroup{Customers: []objects.Customer{
+		{User: &objects.User{ID: "c2", Name: "Jane"}},
+		{User: &objects.User{ID: "c1"}},
+	}}
+	result, err := agent.NewDeduplicator(api, "dedup").WithMaxChatPages(1).Consolidate(group)
+	if err != nil {
+		t.Fatalf("Consolidate failed: %v", err)
+	}
+	if !result.Truncated || len(result.TaggedChats) != 1 {
+		t.Errorf("chats should be reported as truncated: %+v", result)
+	}
+}
@@ -0,0 +1,310 @@
+package agent
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/livechat/lc-sdk-go/v2/objects"
+)
+
+// CustomerProfile aggregates information about a customer gathered from several Agent API methods.
+type CustomerProfile struct {
+	Customer objects.Customer
+	// Chats lists summaries of customer's chats, most recent first.
+	Chats []objects.ChatSummary
+	// Threads maps chat IDs to their most recent threads.
+	Threads map[string][]objects.Thread
+	// Tags lists unique tags of fetched threads.
+	Tags []string
+	// Pages lists pages opened during customer's last visit, most recent first.
+	Pages []PageView
+	// FetchedAt is the time the profile was built at.
+	FetchedAt time.Time
+}
+
+// PageView represents single page opened by a customer.
+type PageView struct {
+	URL      string
+	Title    string
+	OpenedAt time.Time
+}
+
+// ProfileBuilder builds CustomerProfiles.
+//
+// Customer's chats are found by listing chats (optionally narrowed with chats filters, ie. by a property
+// identifying the customer) and selecting those the customer participates in.
+type ProfileBuilder struct {
+	api            *API
+	filters        *chatsFilters
+	maxChats       uint
+	threadsPerChat uint
+	maxPages       uint
+	concurrency    int
+	ttl            time.Duration
+	now            func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]*CustomerProfile
+}
+
+// Default depth of ProfileBuilder.
+const (
+	DefaultProfileMaxChats       = 10
+	DefaultProfileThreadsPerChat = 1
+	DefaultProfileMaxPages       = 5
+)
+
+const profileChatsPageLimit = 100
+
+// NewProfileBuilder creates ProfileBuilder gathering DefaultProfileMaxChats chats with DefaultProfileThreadsPerChat
+// threads each and scanning up to DefaultProfileMaxPages pages of chats. Caching is disabled by default.
+func NewProfileBuilder(api *API) *ProfileBuilder {
+	return &ProfileBuilder{
+		api:            api,
+		filters:        NewChatsFilters(),
+		maxChats:       DefaultProfileMaxChats,
+		threadsPerChat: DefaultProfileThreadsPerChat,
+		maxPages:       DefaultProfileMaxPages,
+		concurrency:    4,
+		now:            time.Now,
+		cache:          make(map[string]*CustomerProfile),
+	}
+}
+
+// WithChatsFilters sets filters used to list chats of the customer.
+func (pb *ProfileBuilder) WithChatsFilters(filters *chatsFilters) *ProfileBuilder {
+	pb.filters = filters
+	return pb
+}
+
+// WithDepth sets maximum number of customer's chats and number of recent threads fetched for each of them.
+// Threads are not fetched if threadsPerChat is 0.
+func (pb *ProfileBuilder) WithDepth(maxChats, threadsPerChat uint) *ProfileBuilder {
+	pb.maxChats = maxChats
+	pb.threadsPerChat = threadsPerChat
+	return pb
+}
+
+// WithMaxPages sets maximum number of pages of chats scanned in search for customer's chats.
+func (pb *ProfileBuilder) WithMaxPages(maxPages uint) *ProfileBuilder {
+	pb.maxPages = maxPages
+	return pb
+}
+
+// WithConcurrency sets maximum number of requests sent at the same time while fetching threads.
+func (pb *ProfileBuilder) WithConcurrency(n int) *ProfileBuilder {
+	pb.concurrency = n
+	return pb
+}
+
+// WithCache enables caching built profiles for given time. Expired profiles are removed whenever a new
+// profile is cached.
+func (pb *ProfileBuilder) WithCache(ttl time.Duration) *ProfileBuilder {
+	pb.ttl = ttl
+	return pb
+}
+
+// WithClock replaces function used by ProfileBuilder to get current time.
+func (pb *ProfileBuilder) WithClock(now func() time.Time) *ProfileBuilder {
+	pb.now = now
+	return pb
+}
+
+// Invalidate removes profile of given customer from cache.
+func (pb *ProfileBuilder) Invalidate(customerID string) {
+	pb.mu.Lock()
+	defer pb.mu.Unlock()
+	delete(pb.cache, customerID)
+}
+
+// Build returns profile of given customer. Cached profiles are shared and should not be modified.
+func (pb *ProfileBuilder) Build(customerID string) (*CustomerProfile, error) {
+	if profile := pb.cached(customerID); profile != nil {
+		return profile, nil
+	}
+
+	profile := &CustomerProfile{
+		Threads:   make(map[string][]objects.Thread),
+		FetchedAt: pb.now(),
+	}
+	var customerErr, chatsErr error
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		profile.Customer, customerErr = pb.api.GetCustomer(customerID)
+	}()
+	go func() {
+		defer wg.Done()
+		var chats map[string][]objects.ChatSummary
+		chats, _, chatsErr = pb.api.customersChats(pb.filters, []string{customerID}, pb.maxChats, pb.maxPages)
+		profile.Chats = chats[customerID]
+	}()
+	wg.Wait()
+	if customerErr != nil {
+		return nil, fmt.Errorf("couldn't get customer: %v", customerErr)
+	}
+	if chatsErr != nil {
+		return nil, fmt.Errorf("couldn't list customer's chats: %v", chatsErr)
+	}
+
+	if err := pb.fetchThreads(profile); err != nil {
+		return nil, err
+	}
+	profile.Tags = collectTags(profile)
+	for _, p := range profile.Customer.LastVisit.LastPages {
+		profile.Pages = append(profile.Pages, PageView{URL: p.URL, Title: p.Title, OpenedAt: p.OpenedAt})
+	}
+	sort.SliceStable(profile.Pages, func(i, j int) bool {
+		return profile.Pages[i].OpenedAt.After(profile.Pages[j].OpenedAt)
+	})
+
+	if pb.ttl > 0 {
+		pb.mu.Lock()
+		pb.sweep()
+		pb.cache[customerID] = profile
+		pb.mu.Unlock()
+	}
+	return profile, nil
+}
+
+// sweep removes expired profiles, so that cache of profiles which are never requested again doesn't grow
+// indefinitely. It has to be called with pb.mu locked.
+func (pb *ProfileBuilder) sweep() {
+	now := pb.now()
+	for id, profile := range pb.cache {
+		if now.Sub(profile.FetchedAt) >= pb.ttl {
+			delete(pb.cache, id)
+		}
+	}
+}
+
+func (pb *ProfileBuilder) cached(customerID string) *CustomerProfile {
+	if pb.ttl <= 0 {
+		return nil
+	}
+	pb.mu.Lock()
+	defer pb.mu.Unlock()
+	profile, exists := pb.cache[customerID]
+	if !exists || pb.now().Sub(profile.FetchedAt) >= pb.ttl {
+		delete(pb.cache, customerID)
+		return nil
+	}
+	return profile
+}
+
+// customersChats lists chats matching filters (scanning at most maxPages pages) and returns up to maxChats chats
+// of each of given customers, most recent first. Truncated is true if page limit was reached before all chats
+// were found.
+func (a *API) customersChats(filters *chatsFilters, customerIDs []string, maxChats, maxPages uint) (chats map[string][]objects.ChatSummary, truncated bool, err error) {
+	chats = make(map[string][]objects.ChatSummary, len(customerIDs))
+	complete := func() bool {
+		for _, id := range customerIDs {
+			if uint(len(chats[id])) < maxChats {
+				return false
+			}
+		}
+		return true
+	}
+
+	pageID := ""
+	for page := uint(0); !complete(); page++ {
+		if page == maxPages {
+			return chats, true, nil
+		}
+		summaries, _, _, nextPage, err := a.ListChats(filters, "desc", pageID, profileChatsPageLimit)
+		if err != nil {
+			return nil, false, err
+		}
+		for _, s := range summaries {
+			for _, id := range customerIDs {
+				if participates(s, id) && uint(len(chats[id])) < maxChats {
+					chats[id] = append(chats[id], s)
+				}
+			}
+		}
+		if nextPage == "" {
+			break
+		}
+		pageID = nextPage
+	}
+	return chats, false, nil
+}
+
+func participates(summary objects.ChatSummary, userID string) bool {
+	for _, u := range summary.Users {
+		if u != nil && u.ID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+func (pb *ProfileBuilder) fetchThreads(profile *CustomerProfile) error {
+	if pb.threadsPerChat == 0 || len(profile.Chats) == 0 {
+		return nil
+	}
+	concurrency := pb.concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	var mu sync.Mutex
+	var errs []string
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for _, chat := range profile.Chats {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(chatID string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			threads, _, _, _, err := pb.api.ListThreads(chatID, "desc", "", pb.threadsPerChat, 0)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("%s: %v", chatID, err))
+				return
+			}
+			profile.Threads[chatID] = threads
+		}(chat.ID)
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		sort.Strings(errs)
+		return fmt.Errorf("couldn't list threads: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+func collectTags(profile *CustomerProfile) []string {
+	seen := make(map[string]bool)
+	var tags []string
+	add := func(tag string) {
+		if !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+	for _, chat := range profile.Chats {
+		if chat.LastThreadSummary != nil {
+			for _, tag := range chat.LastThreadSummary.Tags {
+				add(tag)
+			}
+		}
+		for _, thread := range profile.Threads[chat.ID] {
+			for _, tag := range thread.Tags {
+				add(tag)
+			}
+		}
+	}
+	sort.Strings(tags)
+	return tags
+}
@@ -0,0 +1,126 @@
+package agent_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/livechat/lc-sdk-go/v2/agent"
+)
+
+var profileResponses = map[string]string{
+	"get_customer": `{
+		"id": "b7eff798-f8df-4364-8059-649c35c9ed0c",
+		"type": "customer",
+		"name": "Jane",
+		"last_visit": {
+			"last_pages": [
+				{"opened_at": "2020-05-07T07:10:00.000000Z", "url": "https://example.com/", "title": "Home"},
+				{"opened_at": "2020-05-07T07:11:00.000000Z", "url": "https://example.com/pricing", "title": "Pricing"}
+			]
+		}
+	}`,
+	"list_chats": `{
+		"chats_summary": [
+			{"id": "PJ0MRSHTDG", "users": [{"id": "b7eff798-f8df-4364-8059-649c35c9ed0c", "type": "customer"}], "last_thread_summary": {"id": "K600PKZON8", "tags": ["sales"]}},
+			{"id": "OTHERCHAT1", "users": [{"id": "someone-else", "type": "customer"}]}
+		],
+		"found_chats": 2
+	}`,
+	"list_threads": `{
+		"threads": [{"id": "K600PKZON8", "tags": ["refund", "sales"], "events": []}],
+		"found_threads": 1
+	}`,
+}
+
+func createProfileResponder(t *testing.T, calls map[string]int, mu *sync.Mutex) roundTripFunc {
+	return func(req *http.Request) *http.Response {
+		action := path.Base(req.URL.Path)
+		mu.Lock()
+		calls[action]++
+		mu.Unlock()
+
+		body, exists := profileResponses[action]
+		if !exists {
+			t.Errorf("unexpected action: %v", action)
+		}
+		return &http.Response{
+			StatusCode: 200,
+			Body:       ioutil.NopCloser(bytes.NewBufferString(body)),
+			Header:     make(http.Header),
+		}
+	}
+}
+
+func TestProfileBuilderBuild(t *testing.T) {
+	var mu sync.Mutex
+	calls := make(map[string]int)
+	api, err := agent.NewAPI(stubBearerTokenGetter, NewTestClient(createProfileResponder(t, calls, &mu)), "client_id")
+	if err != nil {
+		t.Errorf("API creation failed")
+	}
+
+	profile, err := agent.NewProfileBuilder(api).WithDepth(5, 2).Build("b7eff798-f8df-4364-8059-649c35c9ed0c")
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	if profile.Customer.User == nil || profile.Customer.Name != "Jane" {
+		t.Errorf("invalid customer: %+v", profile.Customer)
+	}
+	if len(profile.Chats) != 1 || profile.Chats[0].ID != "PJ0MRSHTDG" {
+		t.Errorf("invalid chats: %+v", profile.Chats)
+	}
+	if threads := profile.Threads["PJ0MRSHTDG"]; len(threads) != 1 || threads[0].ID != "K600PKZON8" {
+		t.Errorf("invalid threads: %+v", profile.Threads)
+	}
+	if len(profile.Tags) != 2 || profile.Tags[0] != "refund" || profile.Tags[1] != "sales" {
+		t.Errorf("invalid tags: %v", profile.Tags)
+	}
+	if len(profile.Pages) != 2 || profile.Pages[0].Title != "Pricing" {
+		t.Errorf("invalid pages: %+v", profile.Pages)
+	}
+	if calls["list_threads"] != 1 {
+		t.Errorf("threads should be listed only for customer's chats: %v", calls)
+	}
+}
+
+func TestProfileBuilderShouldCacheProfiles(t *testing.T) {
+	var mu sync.Mutex
+	calls := make(map[string]int)
+	api, err := agent.NewAPI(stubBearerTokenGetter, NewTestClient(createProfileResponder(t, calls, &mu)), "client_id")
+	if err != nil {
+		t.Errorf("API creation failed")
+	}
+
+	now := time.Date(2020, 5, 7, 8, 0, 0, 0, time.UTC)
+	pb := agent.NewProfileBuilder(api).
+		WithDepth(5, 0).
+		WithCache(time.Minute).
+		WithClock(func() time.Time { return now })
+
+	for i := 0; i < 2; i++ {
+		if _, err := pb.Build("b7eff798-f8df-4364-8059-649c35c9ed0c"); err != nil {
+			t.Fatalf("Build failed: %v", err)
+		}
+	}
+	if calls["get_customer"] != 1 || calls["list_threads"] != 0 {
+		t.Errorf("profile should be cached: %v", calls)
+	}
+
+	now = now.Add(time.Minute)
+	if _, err := pb.Build("b7eff798-f8df-4364-8059-649c35c9ed0c"); err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	pb.Invalidate("b7eff798-f8df-4364-8059-649c35c9ed0c")
+	if _, err := pb.Build("b7eff798-f8df-4364-8059-649c35c9ed0c"); err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	if calls["get_customer"] != 3 {
+		t.Errorf("expired and invalidated profiles should be rebuilt: %v", calls)
+	}
+}
@@ -1,5 +1,10 @@
 package agent
 
+import (
+	"fmt"
+	"time"
+)
+
 // PropertiesFilters represents set of filters for Chat properties
 type PropertiesFilters map[string]map[string]*propertyFilterType
 
@@ -92,12 +97,38 @@ func (af *archivesFilters) FromDate(date string) *archivesFilters {
 	return af
 }
 
-// FromDate extends archives filter to exclude entries after given date
+// ToDate extends archives filter to exclude entries after given date
 func (af *archivesFilters) ToDate(date string) *archivesFilters {
 	af.To = date
 	return af
 }
 
+// FromTime extends archives filter to exclude entries before given time
+func (af *archivesFilters) FromTime(t time.Time) *archivesFilters {
+	return af.FromDate(FormatDate(t))
+}
+
+// ToTime extends archives filter to exclude entries after given time
+func (af *archivesFilters) ToTime(t time.Time) *archivesFilters {
+	return af.ToDate(FormatDate(t))
+}
+
+// InRange extends archives filter to exclude entries outside of given time range
+func (af *archivesFilters) InRange(r TimeRange) *archivesFilters {
+	return af.FromTime(r.From).ToTime(r.To)
+}
+
+// Validate checks if archives filter does not contain contradictory time range
+// Dates which cannot be parsed are not compared
+func (af *archivesFilters) Validate() error {
+	from, fromErr := parseDate(af.From)
+	to, toErr := parseDate(af.To)
+	if fromErr == nil && toErr == nil && from.After(to) {
+		return fmt.Errorf("invalid archives filters: from (%s) is after to (%s)", af.From, af.To)
+	}
+	return nil
+}
+
 // ByProperties extends archives filter with Chat properties to match
 func (af *archivesFilters) ByProperties(propsFilters PropertiesFilters) *archivesFilters {
 	af.Properties = propsFilters
@@ -199,6 +230,142 @@ type DateRangeFilter struct {
 	EQ  string `json:"eq,omitempty"`
 }
 
+// NewDateRangeFilter creates empty date range filter
+func NewDateRangeFilter() *DateRangeFilter {
+	return &DateRangeFilter{}
+}
+
+// Until extends date range filter to match dates before or equal to given time (LTE)
+func (drf *DateRangeFilter) Until(t time.Time) *DateRangeFilter {
+	drf.LTE = FormatDate(t)
+	return drf
+}
+
+// Before extends date range filter to match dates before given time (LT)
+func (drf *DateRangeFilter) Before(t time.Time) *DateRangeFilter {
+	drf.LT = FormatDate(t)
+	return drf
+}
+
+// Since extends date range filter to match dates after or equal to given time (GTE)
+func (drf *DateRangeFilter) Since(t time.Time) *DateRangeFilter {
+	drf.GTE = FormatDate(t)
+	return drf
+}
+
+// After extends date range filter to match dates after given time (GT)
+func (drf *DateRangeFilter) After(t time.Time) *DateRangeFilter {
+	drf.GT = FormatDate(t)
+	return drf
+}
+
+// At extends date range filter to match dates equal to given time (EQ)
+func (drf *DateRangeFilter) At(t time.Time) *DateRangeFilter {
+	drf.EQ = FormatDate(t)
+	return drf
+}
+
+// Validate checks if date range filter is not contradictory, ie. if there are dates matching all of its bounds
+// Dates which cannot be parsed are not compared
+func (drf *DateRangeFilter) Validate() error {
+	if drf == nil {
+		return nil
+	}
+	var b bounds
+	for _, bound := range []struct {
+		value string
+		apply func(time.Time)
+	}{
+		{drf.GT, func(t time.Time) { b.lower(t, true) }},
+		{drf.GTE, func(t time.Time) { b.lower(t, false) }},
+		{drf.LT, func(t time.Time) { b.upper(t, true) }},
+		{drf.LTE, func(t time.Time) { b.upper(t, false) }},
+		{drf.EQ, func(t time.Time) { b.lower(t, false); b.upper(t, false) }},
+	} {
+		if t, err := parseDate(bound.value); err == nil {
+			bound.apply(t)
+		}
+	}
+	if b.empty() {
+		return fmt.Errorf("contradictory date range: %+v", *drf)
+	}
+	return nil
+}
+
+// DateFormat is a format of dates accepted by filters (ISO 8601 with microseconds resolution)
+const DateFormat = "2006-01-02T15:04:05.000000Z07:00"
+
+// FormatDate formats given time according to DateFormat
+func FormatDate(t time.Time) string {
+	return t.Format(DateFormat)
+}
+
+func parseDate(date string) (time.Time, error) {
+	return time.Parse(time.RFC3339Nano, date)
+}
+
+// bounds keeps the narrowest lower and upper bounds of a range
+type bounds struct {
+	hasMin, hasMax       bool
+	min, max             time.Time
+	minStrict, maxStrict bool
+}
+
+func (b *bounds) lower(t time.Time, strict bool) {
+	if !b.hasMin || t.After(b.min) || (t.Equal(b.min) && strict) {
+		b.hasMin, b.min, b.minStrict = true, t, strict
+	}
+}
+
+func (b *bounds) upper(t time.Time, strict bool) {
+	if !b.hasMax || t.Before(b.max) || (t.Equal(b.max) && strict) {
+		b.hasMax, b.max, b.maxStrict = true, t, strict
+	}
+}
+
+func (b *bounds) empty() bool {
+	if !b.hasMin || !b.hasMax {
+		return false
+	}
+	return b.min.After(b.max) || (b.min.Equal(b.max) && (b.minStrict || b.maxStrict))
+}
+
+// TimeRange represents period of time between From and To
+type TimeRange struct {
+	From time.Time
+	To   time.Time
+}
+
+// LastNDays returns time range starting at midnight n-1 days before now in given location and ending now,
+// ie. LastNDays(1, ...) returns time range covering today
+func LastNDays(n int, now time.Time, loc *time.Location) TimeRange {
+	now = now.In(loc)
+	y, m, d := now.Date()
+	return TimeRange{
+		From: time.Date(y, m, d-n+1, 0, 0, 0, 0, loc),
+		To:   now,
+	}
+}
+
+// ThisWeek returns time range starting on Monday midnight of the current week in given location and ending now
+func ThisWeek(now time.Time, loc *time.Location) TimeRange {
+	days := (int(now.In(loc).Weekday()) + 6) % 7
+	return LastNDays(days+1, now, loc)
+}
+
+// DateRangeFilter creates date range filter matching dates within the time range
+func (r TimeRange) DateRangeFilter() *DateRangeFilter {
+	return NewDateRangeFilter().Since(r.From).Until(r.To)
+}
+
+// Validate checks if time range does not end before it starts
+func (r TimeRange) Validate() error {
+	if r.From.After(r.To) {
+		return fmt.Errorf("invalid time range: from (%s) is after to (%s)", FormatDate(r.From), FormatDate(r.To))
+	}
+	return nil
+}
+
 // NewCustomersFilters creates empty structure to aggregate filters for customers in ListCustomers method
 func NewCustomersFilters() *customersFilters {
 	return &customersFilters{}
@@ -274,9 +441,26 @@ func (cf *customersFilters) ByCustomersLastActivity(timeRange *DateRangeFilter)
 	return cf
 }
 
+// Validate checks if customers filters do not contain contradictory date ranges
+func (cf *customersFilters) Validate() error {
+	for _, f := range []struct {
+		name string
+		drf  *DateRangeFilter
+	}{
+		{"created_at", cf.CreatedAt},
+		{"agent_last_event_created_at", cf.AgentLastEventCreatedAt},
+		{"customer_last_event_created_at", cf.CustomerLastEventCreatedAt},
+	} {
+		if err := f.drf.Validate(); err != nil {
+			return fmt.Errorf("invalid customers filters: %s: %v", f.name, err)
+		}
+	}
+	return nil
+}
+
 // Chats Filters
 type chatsFilters struct {
-	IncludeActive              bool              `json:"include_active,omitempty"`
+	IncludeActive              bool              `json:"include_active"`
 	IncludeChatsWithoutThreads bool              `json:"include_chats_without_threads,omitempty"`
 	GroupIDs                   []uint            `json:"group_ids,omitempty"`
 	Properties                 PropertiesFilters `json:"properties,omitempty"`
@@ -0,0 +1,275 @@
+package agent
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/livechat/lc-sdk-go/v2/objects"
+)
+
+// Match checks if given property values satisfy property filter
+// Values should be empty if the property is not set
+func (pft *propertyFilterType) Match(values ...interface{}) bool {
+	if pft == nil {
+		return true
+	}
+	if pft.Exists != nil && (len(values) > 0) != *pft.Exists {
+		return false
+	}
+	if pft.Values != nil && !containsAny(pft.Values, values) {
+		return false
+	}
+	if pft.ExcludeValues != nil && containsAny(pft.ExcludeValues, values) {
+		return false
+	}
+	return true
+}
+
+// Match checks if given properties satisfy all property filters
+func (pf PropertiesFilters) Match(props objects.Properties) bool {
+	for namespace, filters := range pf {
+		for name, filter := range filters {
+			value, exists := props[namespace][name]
+			if !exists && !filter.Match() || exists && !filter.Match(value) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Match checks if any of given values satisfies string filter
+func (sf *stringFilter) Match(values ...string) bool {
+	if sf == nil {
+		return true
+	}
+	contains := func(list []string) bool {
+		for _, l := range list {
+			for _, v := range values {
+				if l == v {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	if sf.Values != nil && !contains(sf.Values) {
+		return false
+	}
+	return sf.ExcludeValues == nil || !contains(sf.ExcludeValues)
+}
+
+// Match checks if given number falls within range filter
+// Zero bounds are treated as not set, as they are not sent to the API
+func (rf *RangeFilter) Match(n int) bool {
+	if rf == nil {
+		return true
+	}
+	return (rf.LTE == 0 || n <= rf.LTE) &&
+		(rf.LT == 0 || n < rf.LT) &&
+		(rf.GTE == 0 || n >= rf.GTE) &&
+		(rf.GT == 0 || n > rf.GT) &&
+		(rf.EQ == 0 || n == rf.EQ)
+}
+
+// Match checks if given time falls within date range filter
+// Dates which cannot be parsed are not compared
+func (drf *DateRangeFilter) Match(t time.Time) bool {
+	if drf == nil {
+		return true
+	}
+	for _, bound := range []struct {
+		value string
+		match func(time.Time) bool
+	}{
+		{drf.LTE, func(b time.Time) bool { return !t.After(b) }},
+		{drf.LT, func(b time.Time) bool { return t.Before(b) }},
+		{drf.GTE, func(b time.Time) bool { return !t.Before(b) }},
+		{drf.GT, func(b time.Time) bool { return t.After(b) }},
+		{drf.EQ, func(b time.Time) bool { return t.Equal(b) }},
+	} {
+		if b, err := parseDate(bound.value); err == nil && !bound.match(b) {
+			return false
+		}
+	}
+	return true
+}
+
+// Match checks if given customer satisfies customers filters
+// Country is matched against both name and code of the country of customer's last visit
+func (cf *customersFilters) Match(customer objects.Customer) bool {
+	user := customer.User
+	if user == nil {
+		user = &objects.User{}
+	}
+	geolocation := customer.LastVisit.Geolocation
+	return cf.Country.Match(geolocation.Country, geolocation.CountryCode) &&
+		cf.Email.Match(user.Email) &&
+		cf.Name.Match(user.Name) &&
+		cf.CustomerID.Match(user.ID) &&
+		cf.ChatsCount.Match(customer.Statistics.ChatsCount) &&
+		cf.ThreadsCount.Match(customer.Statistics.ThreadsCount) &&
+		cf.VisitsCount.Match(customer.Statistics.VisitsCount) &&
+		cf.CreatedAt.Match(customer.CreatedAt) &&
+		cf.AgentLastEventCreatedAt.Match(customer.AgentLastEventCreatedAt) &&
+		cf.CustomerLastEventCreatedAt.Match(customer.CustomerLastEventCreatedAt)
+}
+
+// MatchChat checks if given chat satisfies chat filters
+func (cf *chatsFilters) MatchChat(chat objects.Chat) bool {
+	hasThread := chat.Thread.ID != "" || len(chat.Threads) > 0
+	active := chat.Thread.Active
+	for _, t := range chat.Threads {
+		active = active || t.Active
+	}
+	return cf.match(hasThread, active, chat.Access, chat.Properties)
+}
+
+// MatchSummary checks if given chat summary satisfies chat filters
+func (cf *chatsFilters) MatchSummary(summary objects.ChatSummary) bool {
+	thread := summary.LastThreadSummary
+	return cf.match(thread != nil, thread != nil && thread.Active, summary.Access, summary.Properties)
+}
+
+func (cf *chatsFilters) match(hasThread, active bool, access objects.Access, props objects.Properties) bool {
+	switch {
+	case !cf.IncludeChatsWithoutThreads && !hasThread:
+		return false
+	case !cf.IncludeActive && active:
+		return false
+	case cf.GroupIDs != nil && !containsGroup(cf.GroupIDs, access.GroupIDs):
+		return false
+	}
+	return cf.Properties.Match(props)
+}
+
+// Match checks if archived thread of given chat (chat.Thread) satisfies archives filters
+//
+// Agents are taken from chat.Agents, groups and properties from both the thread and the chat,
+// while query is matched against texts of thread's messages ignoring case.
+// Filters by tags, sales, goals and surveys cannot be evaluated locally and result in an error.
+func (af *archivesFilters) Match(chat objects.Chat) (bool, error) {
+	switch {
+	case af.Tags != nil:
+		return false, fmt.Errorf("tags filter cannot be evaluated locally")
+	case af.Sales != nil:
+		return false, fmt.Errorf("sales filter cannot be evaluated locally")
+	case af.Goals != nil:
+		return false, fmt.Errorf("goals filter cannot be evaluated locally")
+	case af.Surveys != nil:
+		return false, fmt.Errorf("surveys filter cannot be evaluated locally")
+	}
+
+	thread := chat.Thread
+	if af.ThreadIDs != nil {
+		return containsString(af.ThreadIDs, thread.ID), nil
+	}
+
+	agents := make([]interface{}, 0, len(chat.Agents))
+	for id := range chat.Agents {
+		agents = append(agents, id)
+	}
+	if !af.Agents.Match(agents...) {
+		return false, nil
+	}
+
+	if af.GroupIDs != nil && !containsGroup(af.GroupIDs, thread.Access.GroupIDs) && !containsGroup(af.GroupIDs, chat.Access.GroupIDs) {
+		return false, nil
+	}
+
+	if !(&DateRangeFilter{GTE: af.From, LTE: af.To}).Match(thread.CreatedAt) {
+		return false, nil
+	}
+
+	props := make(objects.Properties, len(chat.Properties)+len(thread.Properties))
+	for _, source := range []objects.Properties{chat.Properties, thread.Properties} {
+		for namespace, values := range source {
+			if props[namespace] == nil {
+				props[namespace] = make(map[string]interface{}, len(values))
+			}
+			for name, value := range values {
+				props[namespace][name] = value
+			}
+		}
+	}
+	if !af.Properties.Match(props) {
+		return false, nil
+	}
+
+	if af.Events != nil && af.Events.Types != nil {
+		found := false
+		for _, e := range thread.Events {
+			found = found || containsString(af.Events.Types, e.Type)
+		}
+		if !found {
+			return false, nil
+		}
+	}
+
+	if af.Query != "" {
+		query := strings.ToLower(af.Query)
+		found := false
+		for _, e := range thread.Events {
+			if m := e.Message(); m != nil && strings.Contains(strings.ToLower(m.Text), query) {
+				found = true
+				break
+			}
+		}
+		return found, nil
+	}
+	return true, nil
+}
+
+func containsAny(list, values []interface{}) bool {
+	for _, l := range list {
+		for _, v := range values {
+			if equalValues(l, v) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// equalValues compares property values, treating numbers of different types as equal if their values are equal
+func equalValues(a, b interface{}) bool {
+	if x, ok := toFloat(a); ok {
+		y, ok := toFloat(b)
+		return ok && x == y
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := reflect.ValueOf(v); n.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(n.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(n.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return n.Float(), true
+	}
+	return 0, false
+}
+
+func containsGroup(list []uint, groupIDs []int) bool {
+	for _, l := range list {
+		for _, id := range groupIDs {
+			if id >= 0 && uint(id) == l {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func containsString(list []string, value string) bool {
+	for _, l := range list {
+		if l == value {
+			return true
+		}
+	}
+	return false
+}
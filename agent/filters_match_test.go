@@ -0,0 +1,163 @@
+package agent_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/livechat/lc-sdk-go/v2/agent"
+	"github.com/livechat/lc-sdk-go/v2/objects"
+)
+
+func TestPropertyFilterTypeMatch(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		matches bool
+		values  []interface{}
+	}{
+		{"exists", true, []interface{}{"x"}},
+		{"exists", false, nil},
+		{"not exists", true, nil},
+		{"values", true, []interface{}{float64(1)}},
+		{"values", false, []interface{}{float64(3)}},
+		{"exclude values", true, []interface{}{"c"}},
+		{"exclude values", false, []interface{}{"a"}},
+	} {
+		pft := map[string]interface{}{
+			"exists":         agent.NewPropertyFilterType(true),
+			"not exists":     agent.NewPropertyFilterType(false),
+			"values":         agent.NewPropertyFilterType(true, 1, 2),
+			"exclude values": agent.NewPropertyFilterType(false, "a", "b"),
+		}[tc.name].(interface{ Match(...interface{}) bool })
+
+		if pft.Match(tc.values...) != tc.matches {
+			t.Errorf("invalid match of %s filter for %v", tc.name, tc.values)
+		}
+	}
+}
+
+func TestRangeFiltersMatch(t *testing.T) {
+	rf := &agent.RangeFilter{GTE: 2, LT: 5}
+	if !rf.Match(2) || !rf.Match(4) || rf.Match(5) || rf.Match(1) {
+		t.Errorf("invalid RangeFilter match")
+	}
+
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	drf := agent.NewDateRangeFilter().After(now.Add(-time.Hour)).Until(now)
+	if !drf.Match(now) || drf.Match(now.Add(time.Second)) || drf.Match(now.Add(-time.Hour)) {
+		t.Errorf("invalid DateRangeFilter match")
+	}
+}
+
+func TestCustomersFiltersMatch(t *testing.T) {
+	var customer objects.Customer
+	if err := json.Unmarshal([]byte(`{
+		"id": "b7eff798-f8df-4364-8059-649c35c9ed0c",
+		"type": "customer",
+		"name": "Jane",
+		"email": "jane@example.com",
+		"last_visit": {"geolocation": {"country": "Poland", "country_code": "PL"}},
+		"statistics": {"chats_count": 3},
+		"created_at": "2020-01-01T10:00:00.000000Z"
+	}`), &customer); err != nil {
+		t.Fatalf("couldn't unmarshal customer: %v", err)
+	}
+
+	since := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	matching := agent.NewCustomersFilters().
+		ByCountry([]string{"PL"}, true).
+		ByEmail([]string{"john@example.com"}, false).
+		ByChatsCount(&agent.RangeFilter{GT: 2}).
+		ByCreationTime(agent.NewDateRangeFilter().Since(since))
+	if !matching.Match(customer) {
+		t.Errorf("customer should match filters")
+	}
+
+	for _, cf := range []interface{ Match(objects.Customer) bool }{
+		agent.NewCustomersFilters().ByCountry([]string{"Germany"}, true),
+		agent.NewCustomersFilters().ByName([]string{"Jane"}, false),
+		agent.NewCustomersFilters().ByChatsCount(&agent.RangeFilter{LT: 3}),
+		agent.NewCustomersFilters().ByCustomersLastActivity(agent.NewDateRangeFilter().Since(since)),
+	} {
+		if cf.Match(customer) {
+			t.Errorf("customer should not match filters: %+v", cf)
+		}
+	}
+}
+
+func TestChatsFiltersMatch(t *testing.T) {
+	summary := objects.ChatSummary{
+		ID:                "PJ0MRSHTDG",
+		LastThreadSummary: &objects.ThreadSummary{ID: "K600PKZON8", Active: true},
+		Access:            objects.Access{GroupIDs: []int{0, 2}},
+		Properties:        objects.Properties{"routing": {"pinned": true}},
+	}
+
+	if !agent.NewChatsFilters().ByGroups([]uint{2}).MatchSummary(summary) {
+		t.Errorf("chat summary should match filters")
+	}
+	if agent.NewChatsFilters().WithoutActiveChats().MatchSummary(summary) {
+		t.Errorf("active chat should not match filters without active chats")
+	}
+	if agent.NewChatsFilters().ByGroups([]uint{1}).MatchSummary(summary) {
+		t.Errorf("chat from other group should not match filters")
+	}
+	pinned := agent.PropertiesFilters{"routing": {"pinned": agent.NewPropertyFilterType(true, false)}}
+	if agent.NewChatsFilters().ByProperties(pinned).MatchSummary(summary) {
+		t.Errorf("chat with other property value should not match filters")
+	}
+
+	chat := objects.Chat{ID: "PJ0MRSHTDG"}
+	if agent.NewChatsFilters().MatchChat(chat) || !agent.NewChatsFilters().WithChatsWithoutThreads().MatchChat(chat) {
+		t.Errorf("chat without threads should match only filters including them")
+	}
+}
+
+func TestArchivesFiltersMatch(t *testing.T) {
+	var chat objects.Chat
+	if err := json.Unmarshal([]byte(`{
+		"id": "PJ0MRSHTDG",
+		"users": [{"id": "smith@example.com", "type": "agent"}],
+		"properties": {"source": {"app": "mobile"}},
+		"access": {"group_ids": [1]},
+		"thread": {
+			"id": "K600PKZON8",
+			"created_at": "2020-05-07T07:11:28.288340Z",
+			"properties": {"source": {"priority": 2}},
+			"events": [{"id": "Q20N9CKRX2_1", "type": "message", "text": "I'd like a Refund", "author_id": "b7eff798-f8df-4364-8059-649c35c9ed0c"}]
+		}
+	}`), &chat); err != nil {
+		t.Fatalf("couldn't unmarshal chat: %v", err)
+	}
+
+	matching := agent.NewArchivesFilters().
+		ByAgents(true, "smith@example.com").
+		ByGroups([]uint{1}).
+		InRange(agent.TimeRange{From: time.Date(2020, 5, 1, 0, 0, 0, 0, time.UTC), To: time.Date(2020, 5, 8, 0, 0, 0, 0, time.UTC)}).
+		ByProperties(agent.PropertiesFilters{
+			"source": {"app": agent.NewPropertyFilterType(true, "mobile"), "priority": agent.NewPropertyFilterType(true, 2)},
+		}).
+		ByEventTypes("message").
+		ByQuery("refund")
+	if ok, err := matching.Match(chat); !ok || err != nil {
+		t.Errorf("chat should match filters: %v", err)
+	}
+
+	for _, af := range []interface {
+		Match(objects.Chat) (bool, error)
+	}{
+		agent.NewArchivesFilters().ByAgents(false, "smith@example.com"),
+		agent.NewArchivesFilters().FromDate("2020-05-08T00:00:00.000000Z"),
+		agent.NewArchivesFilters().ByEventTypes("file"),
+		agent.NewArchivesFilters().ByQuery("invoice"),
+		agent.NewArchivesFilters().ByThreads([]string{"OTHER"}),
+	} {
+		if ok, err := af.Match(chat); ok || err != nil {
+			t.Errorf("chat should not match filters %+v: %v", af, err)
+		}
+	}
+
+	if _, err := agent.NewArchivesFilters().ByTags(true, "refund").Match(chat); err == nil {
+		t.Errorf("tags filter should not be evaluated locally")
+	}
+}
@@ -0,0 +1,708 @@
+package agent
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Filters can be built from textual queries and printed back with String methods, ie.
+//
+//	tag:refund agent:smith@example.com from:2020-01-01 property:app.priority=high "late delivery"
+//
+// A query consists of terms separated by ASCII whitespace. Terms have `key:value` form, terms prefixed with `-`
+// are negated (ie. exclude given value) and terms without a key are a free-text query. Values containing
+// whitespace or quotes should be quoted with double quotes. Dates are accepted either as `YYYY-MM-DD`
+// (midnight UTC) or in ISO 8601 format.
+//
+// Filters are also JSON round-trippable: they can be marshaled with json.Marshal and decoded back with
+// json.Unmarshal into NewArchivesFilters(), NewCustomersFilters() or NewChatsFilters().
+
+// ParseArchivesQuery creates archives filters from query. Supported terms are:
+//
+//	agent:ID, tag:NAME, sale:ID, goal:ID - match given value, `*` matches any value (ie. agent:*)
+//	group:ID                             - matches given group
+//	from:DATE, to:DATE                   - match entries in given time range
+//	property:NAMESPACE.NAME[=VALUE]      - matches property existence or value
+//	survey:TYPE=ANSWER_ID                - matches survey answer
+//	thread:ID                            - matches given thread, cannot be combined with other terms
+//	event:TYPE                           - matches threads with events of given type
+//	free text                            - matches text of messages
+//
+// Agent, tag, sale, goal and property terms can be negated.
+func ParseArchivesQuery(query string) (*archivesFilters, error) {
+	terms, err := parseQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	af := NewArchivesFilters()
+	var text []string
+	for _, t := range terms {
+		var err error
+		switch t.key {
+		case "":
+			var v string
+			if v, err = t.value(); err == nil {
+				text = append(text, v)
+			}
+		case "agent":
+			af.Agents, err = t.propertyFilter(af.Agents)
+		case "tag":
+			af.Tags, err = t.propertyFilter(af.Tags)
+		case "sale":
+			af.Sales, err = t.propertyFilter(af.Sales)
+		case "goal":
+			af.Goals, err = t.propertyFilter(af.Goals)
+		case "group":
+			var id uint
+			if id, err = t.groupID(); err == nil {
+				af.GroupIDs = append(af.GroupIDs, id)
+			}
+		case "from":
+			af.From, err = t.date()
+		case "to":
+			af.To, err = t.date()
+		case "property":
+			if af.Properties == nil {
+				af.Properties = make(PropertiesFilters)
+			}
+			err = t.propertiesFilter(af.Properties)
+		case "survey":
+			var parts []string
+			if parts, err = t.pair(); err == nil {
+				af.Surveys = append(af.Surveys, SurveyFilter{Type: parts[0], AnswerID: parts[1]})
+			}
+		case "thread":
+			var id string
+			if id, err = t.plainValue(); err == nil {
+				af.ThreadIDs = append(af.ThreadIDs, id)
+			}
+		case "event":
+			var eventType string
+			if eventType, err = t.plainValue(); err == nil {
+				if af.Events == nil {
+					af.Events = &eventsFilter{}
+				}
+				af.Events.Types = append(af.Events.Types, eventType)
+			}
+		default:
+			err = fmt.Errorf("unknown key: %q", t.key)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid query term %q: %v", t.raw, err)
+		}
+	}
+	af.Query = strings.Join(text, " ")
+
+	if af.ThreadIDs != nil && len(terms) != len(af.ThreadIDs) {
+		return nil, fmt.Errorf("invalid query: thread terms cannot be combined with other terms")
+	}
+	return af, nil
+}
+
+// String returns query representing archives filters. See ParseArchivesQuery for query syntax.
+func (af *archivesFilters) String() string {
+	var q query
+	q.propertyFilter("agent", af.Agents)
+	for _, id := range af.GroupIDs {
+		q.add(false, "group", strconv.FormatUint(uint64(id), 10))
+	}
+	if af.From != "" {
+		q.add(false, "from", formatQueryDate(af.From))
+	}
+	if af.To != "" {
+		q.add(false, "to", formatQueryDate(af.To))
+	}
+	q.propertiesFilter(af.Properties)
+	q.propertyFilter("tag", af.Tags)
+	q.propertyFilter("sale", af.Sales)
+	q.propertyFilter("goal", af.Goals)
+	for _, s := range af.Surveys {
+		q.add(false, "survey", quoteQueryValue(s.Type)+"="+quoteQueryValue(s.AnswerID))
+	}
+	for _, id := range af.ThreadIDs {
+		q.add(false, "thread", quoteQueryValue(id))
+	}
+	if af.Events != nil {
+		for _, t := range af.Events.Types {
+			q.add(false, "event", quoteQueryValue(t))
+		}
+	}
+	if af.Query != "" {
+		q.text(af.Query)
+	}
+	return q.String()
+}
+
+// ParseCustomersQuery creates customers filters from query. Supported terms are:
+//
+//	country:NAME, email:EMAIL, name:NAME, id:ID      - match given value, can be negated
+//	chats:N, threads:N, visits:N                     - match counts, N can be prefixed with >, >=, <, <= or =
+//	                                                   and cannot be zero
+//	created:DATE, agent_activity:DATE,
+//	customer_activity:DATE                           - match dates, DATE can be prefixed like counts
+func ParseCustomersQuery(query string) (*customersFilters, error) {
+	terms, err := parseQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	cf := NewCustomersFilters()
+	for _, t := range terms {
+		var err error
+		switch t.key {
+		case "country":
+			cf.Country, err = t.stringFilter(cf.Country)
+		case "email":
+			cf.Email, err = t.stringFilter(cf.Email)
+		case "name":
+			cf.Name, err = t.stringFilter(cf.Name)
+		case "id":
+			cf.CustomerID, err = t.stringFilter(cf.CustomerID)
+		case "chats":
+			cf.ChatsCount, err = t.rangeFilter(cf.ChatsCount)
+		case "threads":
+			cf.ThreadsCount, err = t.rangeFilter(cf.ThreadsCount)
+		case "visits":
+			cf.VisitsCount, err = t.rangeFilter(cf.VisitsCount)
+		case "created":
+			cf.CreatedAt, err = t.dateRangeFilter(cf.CreatedAt)
+		case "agent_activity":
+			cf.AgentLastEventCreatedAt, err = t.dateRangeFilter(cf.AgentLastEventCreatedAt)
+		case "customer_activity":
+			cf.CustomerLastEventCreatedAt, err = t.dateRangeFilter(cf.CustomerLastEventCreatedAt)
+		case "":
+			err = fmt.Errorf("free text is not supported")
+		default:
+			err = fmt.Errorf("unknown key: %q", t.key)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid query term %q: %v", t.raw, err)
+		}
+	}
+	return cf, nil
+}
+
+// String returns query representing customers filters. See ParseCustomersQuery for query syntax.
+func (cf *customersFilters) String() string {
+	var q query
+	q.stringFilter("country", cf.Country)
+	q.stringFilter("email", cf.Email)
+	q.stringFilter("name", cf.Name)
+	q.stringFilter("id", cf.CustomerID)
+	q.rangeFilter("chats", cf.ChatsCount)
+	q.rangeFilter("threads", cf.ThreadsCount)
+	q.rangeFilter("visits", cf.VisitsCount)
+	q.dateRangeFilter("created", cf.CreatedAt)
+	q.dateRangeFilter("agent_activity", cf.AgentLastEventCreatedAt)
+	q.dateRangeFilter("customer_activity", cf.CustomerLastEventCreatedAt)
+	return q.String()
+}
+
+// ParseChatsQuery creates chats filters from query. Supported terms are:
+//
+//	group:ID                        - matches given group
+//	property:NAMESPACE.NAME[=VALUE] - matches property existence or value, can be negated
+//	active:false                    - excludes active chats
+//	without_threads:true            - includes chats without threads
+func ParseChatsQuery(query string) (*chatsFilters, error) {
+	terms, err := parseQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	cf := NewChatsFilters()
+	for _, t := range terms {
+		var err error
+		switch t.key {
+		case "group":
+			var id uint
+			if id, err = t.groupID(); err == nil {
+				cf.GroupIDs = append(cf.GroupIDs, id)
+			}
+		case "property":
+			if cf.Properties == nil {
+				cf.Properties = make(PropertiesFilters)
+			}
+			err = t.propertiesFilter(cf.Properties)
+		case "active":
+			cf.IncludeActive, err = t.bool()
+		case "without_threads":
+			cf.IncludeChatsWithoutThreads, err = t.bool()
+		case "":
+			err = fmt.Errorf("free text is not supported")
+		default:
+			err = fmt.Errorf("unknown key: %q", t.key)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid query term %q: %v", t.raw, err)
+		}
+	}
+	return cf, nil
+}
+
+// String returns query representing chats filters. See ParseChatsQuery for query syntax.
+func (cf *chatsFilters) String() string {
+	var q query
+	for _, id := range cf.GroupIDs {
+		q.add(false, "group", strconv.FormatUint(uint64(id), 10))
+	}
+	q.propertiesFilter(cf.Properties)
+	if !cf.IncludeActive {
+		q.add(false, "active", "false")
+	}
+	if cf.IncludeChatsWithoutThreads {
+		q.add(false, "without_threads", "true")
+	}
+	return q.String()
+}
+
+// Parsing
+
+type term struct {
+	raw     string
+	negated bool
+	key     string
+	// rawValue is the value as it appeared in query, possibly quoted.
+	rawValue string
+}
+
+// value returns unquoted value of the term.
+func (t term) value() (string, error) {
+	v, _, err := unquoteQueryValue(t.rawValue)
+	return v, err
+}
+
+func parseQuery(q string) ([]term, error) {
+	var terms []term
+	for i := 0; i < len(q); {
+		if isQuerySpace(q[i]) {
+			i++
+			continue
+		}
+		start, inQuote := i, false
+		for ; i < len(q) && (inQuote || !isQuerySpace(q[i])); i++ {
+			switch {
+			case inQuote && q[i] == '\\':
+				i++
+			case q[i] == '"':
+				inQuote = !inQuote
+			}
+		}
+		if inQuote {
+			return nil, fmt.Errorf("invalid query: unterminated quote in %q", q[start:])
+		}
+		t, err := parseTerm(q[start:i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid query term %q: %v", q[start:i], err)
+		}
+		terms = append(terms, t)
+	}
+	return terms, nil
+}
+
+// isQuerySpace checks if byte separates terms. Only ASCII whitespace is checked, so that bytes of multi-byte
+// UTF-8 characters are never mistaken for separators.
+func isQuerySpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+func parseTerm(raw string) (term, error) {
+	t := term{raw: raw, rawValue: raw}
+	if strings.HasPrefix(t.rawValue, "-") && len(t.rawValue) > 1 {
+		t.negated = true
+		t.rawValue = t.rawValue[1:]
+	}
+	if i := strings.IndexAny(t.rawValue, `:"`); i > 0 && t.rawValue[i] == ':' {
+		t.key, t.rawValue = t.rawValue[:i], t.rawValue[i+1:]
+	} else if t.negated {
+		return term{}, fmt.Errorf("free text cannot be negated")
+	}
+	return t, nil
+}
+
+func unquoteQueryValue(s string) (string, bool, error) {
+	if !strings.HasPrefix(s, `"`) {
+		return s, false, nil
+	}
+	v, err := strconv.Unquote(s)
+	if err != nil {
+		return "", false, fmt.Errorf("invalid quoted value: %s", s)
+	}
+	return v, true, nil
+}
+
+func (t term) notNegated() error {
+	if t.negated {
+		return fmt.Errorf("%s cannot be negated", t.key)
+	}
+	return nil
+}
+
+// plainValue returns unquoted value of the term which cannot be negated.
+func (t term) plainValue() (string, error) {
+	if err := t.notNegated(); err != nil {
+		return "", err
+	}
+	return t.value()
+}
+
+func (t term) groupID() (uint, error) {
+	v, err := t.plainValue()
+	if err != nil {
+		return 0, err
+	}
+	id, err := strconv.ParseUint(v, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid group ID: %q", v)
+	}
+	return uint(id), nil
+}
+
+func (t term) bool() (bool, error) {
+	v, err := t.plainValue()
+	if err != nil {
+		return false, err
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, fmt.Errorf("invalid boolean: %q", v)
+	}
+	return b, nil
+}
+
+func (t term) date() (string, error) {
+	v, err := t.plainValue()
+	if err != nil {
+		return "", err
+	}
+	return parseQueryDate(v)
+}
+
+// pair splits value into two parts separated with `=`, each of which can be quoted.
+func (t term) pair() ([]string, error) {
+	if err := t.notNegated(); err != nil {
+		return nil, err
+	}
+	i, inQuote := -1, false
+	for j := 0; j < len(t.rawValue) && i < 0; j++ {
+		switch c := t.rawValue[j]; {
+		case inQuote && c == '\\':
+			j++
+		case c == '"':
+			inQuote = !inQuote
+		case c == '=' && !inQuote:
+			i = j
+		}
+	}
+	if i < 0 {
+		return nil, fmt.Errorf("expected value in form of KEY=VALUE")
+	}
+	first, _, err := unquoteQueryValue(t.rawValue[:i])
+	if err != nil {
+		return nil, err
+	}
+	second, _, err := unquoteQueryValue(t.rawValue[i+1:])
+	if err != nil {
+		return nil, err
+	}
+	return []string{first, second}, nil
+}
+
+func (t term) propertyFilter(pft *propertyFilterType) (*propertyFilterType, error) {
+	v, err := t.value()
+	if err != nil {
+		return nil, err
+	}
+	if pft == nil {
+		pft = &propertyFilterType{}
+	}
+	switch {
+	case t.rawValue == "*":
+		exists := !t.negated
+		pft.Exists = &exists
+	case t.negated:
+		pft.ExcludeValues = append(pft.ExcludeValues, v)
+	default:
+		pft.Values = append(pft.Values, v)
+	}
+	return pft, nil
+}
+
+func (t term) propertiesFilter(pf PropertiesFilters) error {
+	name, rawValue := t.rawValue, ""
+	hasValue := false
+	if i := strings.Index(t.rawValue, "="); i >= 0 {
+		name, rawValue, hasValue = t.rawValue[:i], t.rawValue[i+1:], true
+	}
+	parts := strings.SplitN(name, ".", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return fmt.Errorf("expected property in form of NAMESPACE.NAME[=VALUE]")
+	}
+	namespace, property := parts[0], parts[1]
+	if pf[namespace] == nil {
+		pf[namespace] = make(map[string]*propertyFilterType)
+	}
+	pft := pf[namespace][property]
+	if pft == nil {
+		pft = &propertyFilterType{}
+		pf[namespace][property] = pft
+	}
+
+	if !hasValue {
+		exists := !t.negated
+		pft.Exists = &exists
+		return nil
+	}
+	value, err := parsePropertyValue(rawValue)
+	if err != nil {
+		return err
+	}
+	if t.negated {
+		pft.ExcludeValues = append(pft.ExcludeValues, value)
+	} else {
+		pft.Values = append(pft.Values, value)
+	}
+	return nil
+}
+
+// parsePropertyValue parses unquoted numbers and booleans to float64 and bool (as they are decoded from JSON),
+// while other values are returned as strings.
+func parsePropertyValue(raw string) (interface{}, error) {
+	value, quoted, err := unquoteQueryValue(raw)
+	if err != nil || quoted {
+		return value, err
+	}
+	if b, err := strconv.ParseBool(value); err == nil {
+		return b, nil
+	}
+	if f, err := strconv.ParseFloat(value, 64); err == nil {
+		return f, nil
+	}
+	return value, nil
+}
+
+func (t term) stringFilter(sf *stringFilter) (*stringFilter, error) {
+	v, err := t.value()
+	if err != nil {
+		return nil, err
+	}
+	if sf == nil {
+		sf = &stringFilter{}
+	}
+	if t.negated {
+		sf.ExcludeValues = append(sf.ExcludeValues, v)
+	} else {
+		sf.Values = append(sf.Values, v)
+	}
+	return sf, nil
+}
+
+var comparisonOperators = []string{">=", "<=", ">", "<", "="}
+
+func (t term) comparison() (string, string, error) {
+	v, err := t.plainValue()
+	if err != nil {
+		return "", "", err
+	}
+	for _, op := range comparisonOperators {
+		if strings.HasPrefix(v, op) {
+			return op, v[len(op):], nil
+		}
+	}
+	return "=", v, nil
+}
+
+func (t term) rangeFilter(rf *RangeFilter) (*RangeFilter, error) {
+	op, value, err := t.comparison()
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid number: %q", value)
+	}
+	// RangeFilter treats zero bounds as not set, so they would be silently dropped.
+	if n == 0 {
+		return nil, fmt.Errorf("zero cannot be used as a bound")
+	}
+	if rf == nil {
+		rf = &RangeFilter{}
+	}
+	*rangeBound(op, &rf.GTE, &rf.LTE, &rf.GT, &rf.LT, &rf.EQ) = n
+	return rf, nil
+}
+
+func (t term) dateRangeFilter(drf *DateRangeFilter) (*DateRangeFilter, error) {
+	op, value, err := t.comparison()
+	if err != nil {
+		return nil, err
+	}
+	date, err := parseQueryDate(value)
+	if err != nil {
+		return nil, err
+	}
+	if drf == nil {
+		drf = NewDateRangeFilter()
+	}
+	*dateRangeBound(op, drf) = date
+	return drf, nil
+}
+
+func rangeBound(op string, gte, lte, gt, lt, eq *int) *int {
+	return map[string]*int{">=": gte, "<=": lte, ">": gt, "<": lt, "=": eq}[op]
+}
+
+func dateRangeBound(op string, drf *DateRangeFilter) *string {
+	return map[string]*string{">=": &drf.GTE, "<=": &drf.LTE, ">": &drf.GT, "<": &drf.LT, "=": &drf.EQ}[op]
+}
+
+const queryDateFormat = "2006-01-02"
+
+func parseQueryDate(value string) (string, error) {
+	if t, err := time.Parse(queryDateFormat, value); err == nil {
+		return FormatDate(t), nil
+	}
+	t, err := parseDate(value)
+	if err != nil {
+		return "", fmt.Errorf("invalid date: %q, expected YYYY-MM-DD or ISO 8601", value)
+	}
+	return FormatDate(t), nil
+}
+
+// Printing
+
+type query []string
+
+func (q *query) add(negated bool, key, value string) {
+	prefix := ""
+	if negated {
+		prefix = "-"
+	}
+	*q = append(*q, prefix+key+":"+value)
+}
+
+func (q *query) text(text string) {
+	if strings.ContainsAny(text, ` :"\`+"\t\n\r") || strings.HasPrefix(text, "-") {
+		text = strconv.Quote(text)
+	}
+	*q = append(*q, text)
+}
+
+func (q *query) propertyFilter(key string, pft *propertyFilterType) {
+	if pft == nil {
+		return
+	}
+	if pft.Exists != nil {
+		q.add(!*pft.Exists, key, "*")
+	}
+	for _, v := range pft.Values {
+		q.add(false, key, quoteQueryValue(fmt.Sprint(v)))
+	}
+	for _, v := range pft.ExcludeValues {
+		q.add(true, key, quoteQueryValue(fmt.Sprint(v)))
+	}
+}
+
+func (q *query) propertiesFilter(pf PropertiesFilters) {
+	namespaces := make([]string, 0, len(pf))
+	for namespace := range pf {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+	for _, namespace := range namespaces {
+		names := make([]string, 0, len(pf[namespace]))
+		for name := range pf[namespace] {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			pft := pf[namespace][name]
+			if pft == nil {
+				continue
+			}
+			property := namespace + "." + name
+			if pft.Exists != nil {
+				q.add(!*pft.Exists, "property", property)
+			}
+			for _, v := range pft.Values {
+				q.add(false, "property", property+"="+formatPropertyValue(v))
+			}
+			for _, v := range pft.ExcludeValues {
+				q.add(true, "property", property+"="+formatPropertyValue(v))
+			}
+		}
+	}
+}
+
+func (q *query) stringFilter(key string, sf *stringFilter) {
+	if sf == nil {
+		return
+	}
+	for _, v := range sf.Values {
+		q.add(false, key, quoteQueryValue(v))
+	}
+	for _, v := range sf.ExcludeValues {
+		q.add(true, key, quoteQueryValue(v))
+	}
+}
+
+func (q *query) rangeFilter(key string, rf *RangeFilter) {
+	if rf == nil {
+		return
+	}
+	for _, op := range comparisonOperators {
+		if n := *rangeBound(op, &rf.GTE, &rf.LTE, &rf.GT, &rf.LT, &rf.EQ); n != 0 {
+			q.add(false, key, strings.TrimPrefix(op, "=")+strconv.Itoa(n))
+		}
+	}
+}
+
+func (q *query) dateRangeFilter(key string, drf *DateRangeFilter) {
+	if drf == nil {
+		return
+	}
+	for _, op := range comparisonOperators {
+		if date := *dateRangeBound(op, drf); date != "" {
+			q.add(false, key, strings.TrimPrefix(op, "=")+formatQueryDate(date))
+		}
+	}
+}
+
+func (q query) String() string {
+	return strings.Join(q, " ")
+}
+
+// quoteQueryValue quotes values which would not be parsed back as given literal string.
+func quoteQueryValue(v string) string {
+	if v == "" || v == "*" || strings.ContainsAny(v, " \"\\=\t\n\r") {
+		return strconv.Quote(v)
+	}
+	return v
+}
+
+func formatPropertyValue(v interface{}) string {
+	s, ok := v.(string)
+	if !ok {
+		return quoteQueryValue(fmt.Sprint(v))
+	}
+	if parsed, _ := parsePropertyValue(s); parsed != s {
+		return strconv.Quote(s)
+	}
+	return quoteQueryValue(s)
+}
+
+// formatQueryDate shortens dates at midnight UTC to YYYY-MM-DD.
+func formatQueryDate(date string) string {
+	t, err := parseDate(date)
+	if err != nil {
+		return quoteQueryValue(date)
+	}
+	if _, offset := t.Zone(); offset == 0 && t.Equal(t.Truncate(24*time.Hour)) {
+		return t.Format(queryDateFormat)
+	}
+	return FormatDate(t)
+}
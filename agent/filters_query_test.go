@@ -0,0 +1,155 @@
+package agent_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/livechat/lc-sdk-go/v2/agent"
+)
+
+func TestParseArchivesQuery(t *testing.T) {
+	af, err := agent.ParseArchivesQuery(`tag:refund -agent:a@b.com from:2026-01-01 property:app.priority=high property:app.level=2 -property:app.vip group:3 "late delivery"`)
+	if err != nil {
+		t.Fatalf("ParseArchivesQuery failed: %v", err)
+	}
+
+	if af.Tags.Values[0] != "refund" || af.Agents.ExcludeValues[0] != "a@b.com" {
+		t.Errorf("invalid property filters: %v, %v", af.Tags, af.Agents)
+	}
+	if af.From != "2026-01-01T00:00:00.000000Z" || af.GroupIDs[0] != 3 {
+		t.Errorf("invalid simple filters: %v, %v", af.From, af.GroupIDs)
+	}
+	app := af.Properties["app"]
+	if app["priority"].Values[0] != "high" || app["level"].Values[0] != float64(2) || *app["vip"].Exists {
+		t.Errorf("invalid properties filter: %v", app)
+	}
+	if af.Query != "late delivery" {
+		t.Errorf("invalid query: %v", af.Query)
+	}
+
+	af, err = agent.ParseArchivesQuery(`agent:zając@example.com "à la carte"`)
+	if err != nil {
+		t.Fatalf("ParseArchivesQuery failed: %v", err)
+	}
+	if af.Agents.Values[0] != "zając@example.com" || af.Query != "à la carte" {
+		t.Errorf("invalid non-ASCII terms: %v, %v", af.Agents, af.Query)
+	}
+}
+
+func TestArchivesQueryRoundTrip(t *testing.T) {
+	for _, query := range []string{
+		`agent:* group:1 group:2 from:2026-01-01 to:2026-01-31T12:00:00.000000+01:00 -tag:spam`,
+		`property:app.priority=high property:app.size="2" -property:app.vip=true sale:* goal:"with space"`,
+		`survey:"post chat"=yes event:file "refund:partial"`,
+		`thread:K600PKZON8 thread:K600PKZON9`,
+	} {
+		af, err := agent.ParseArchivesQuery(query)
+		if err != nil {
+			t.Errorf("ParseArchivesQuery(%q) failed: %v", query, err)
+			continue
+		}
+		if s := af.String(); s != query {
+			t.Errorf("invalid query: %v, expected: %v", s, query)
+		}
+	}
+}
+
+func TestParseArchivesQueryErrors(t *testing.T) {
+	for _, query := range []string{
+		`unknown:value`,
+		`"unterminated`,
+		`group:abc`,
+		`from:yesterday`,
+		`property:priority=high`,
+		`-thread:K600PKZON8`,
+		`-free`,
+		`thread:K600PKZON8 tag:refund`,
+		`survey:yes`,
+	} {
+		if _, err := agent.ParseArchivesQuery(query); err == nil {
+			t.Errorf("ParseArchivesQuery(%q) should fail", query)
+		}
+	}
+}
+
+func TestCustomersQueryRoundTrip(t *testing.T) {
+	query := `country:PL -email:john@example.com name:"Jane Doe" chats:>=2 chats:<10 visits:3 created:>2026-01-01 customer_activity:<=2026-02-01T10:00:00.000000Z`
+	cf, err := agent.ParseCustomersQuery(query)
+	if err != nil {
+		t.Fatalf("ParseCustomersQuery failed: %v", err)
+	}
+
+	if cf.Name.Values[0] != "Jane Doe" || cf.Email.ExcludeValues[0] != "john@example.com" {
+		t.Errorf("invalid string filters: %v, %v", cf.Name, cf.Email)
+	}
+	if *cf.ChatsCount != (agent.RangeFilter{GTE: 2, LT: 10}) || cf.VisitsCount.EQ != 3 {
+		t.Errorf("invalid range filters: %v, %v", cf.ChatsCount, cf.VisitsCount)
+	}
+	if cf.CreatedAt.GT != "2026-01-01T00:00:00.000000Z" {
+		t.Errorf("invalid date range filter: %v", cf.CreatedAt)
+	}
+	if s := cf.String(); s != query {
+		t.Errorf("invalid query: %v", s)
+	}
+
+	if _, err := agent.ParseCustomersQuery(`-chats:2`); err == nil {
+		t.Errorf("range terms should not be negated")
+	}
+	for _, query := range []string{`chats:0`, `visits:>=0`} {
+		if _, err := agent.ParseCustomersQuery(query); err == nil {
+			t.Errorf("ParseCustomersQuery(%q) should reject zero bound", query)
+		}
+	}
+}
+
+func TestChatsQueryRoundTrip(t *testing.T) {
+	query := `group:5 property:routing.pinned=true active:false without_threads:true`
+	cf, err := agent.ParseChatsQuery(query)
+	if err != nil {
+		t.Fatalf("ParseChatsQuery failed: %v", err)
+	}
+
+	if cf.IncludeActive || !cf.IncludeChatsWithoutThreads || cf.GroupIDs[0] != 5 {
+		t.Errorf("invalid chats filters: %v", cf)
+	}
+	if s := cf.String(); s != query {
+		t.Errorf("invalid query: %v", s)
+	}
+	if s := agent.NewChatsFilters().String(); s != "" {
+		t.Errorf("default filters should be represented with empty query: %v", s)
+	}
+}
+
+func TestFiltersJSONRoundTrip(t *testing.T) {
+	af, err := agent.ParseArchivesQuery(`tag:refund property:app.priority=high from:2026-01-01`)
+	if err != nil {
+		t.Fatalf("ParseArchivesQuery failed: %v", err)
+	}
+	data, err := json.Marshal(af)
+	if err != nil {
+		t.Fatalf("couldn't marshal filters: %v", err)
+	}
+
+	decoded := agent.NewArchivesFilters()
+	if err := json.Unmarshal(data, decoded); err != nil {
+		t.Fatalf("couldn't unmarshal filters: %v", err)
+	}
+	if !reflect.DeepEqual(af, decoded) {
+		t.Errorf("invalid decoded filters: %v", decoded)
+	}
+
+	cf, _ := agent.ParseCustomersQuery(`email:jane@example.com chats:>2`)
+	data, _ = json.Marshal(cf)
+	decodedCustomers := agent.NewCustomersFilters()
+	if err := json.Unmarshal(data, decodedCustomers); err != nil || decodedCustomers.String() != cf.String() {
+		t.Errorf("invalid decoded customers filters: %v, %v", decodedCustomers, err)
+	}
+
+	chf, _ := agent.ParseChatsQuery(`group:5 active:false`)
+	data, _ = json.Marshal(chf)
+	decodedChats := agent.NewChatsFilters()
+	if err := json.Unmarshal(data, decodedChats); err != nil || !reflect.DeepEqual(chf, decodedChats) {
+		t.Errorf("invalid decoded chats filters: %v, %v", decodedChats, err)
+	}
+}
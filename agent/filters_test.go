@@ -2,6 +2,7 @@ package agent_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/livechat/lc-sdk-go/v2/agent"
 )
@@ -286,3 +287,84 @@ func TestChatsFilters(t *testing.T) {
 		t.Errorf("ChatsFilters.GroupIDs invalid: %v", cf.GroupIDs)
 	}
 }
+
+func TestArchivesFiltersTimeFields(t *testing.T) {
+	loc := time.FixedZone("UTC+1", 60*60)
+	af := agent.NewArchivesFilters().
+		FromTime(time.Date(2017, 10, 12, 15, 19, 21, 10200000, loc)).
+		ToTime(time.Date(2017, 10, 12, 14, 19, 22, 0, time.UTC))
+
+	if af.From != "2017-10-12T15:19:21.010200+01:00" {
+		t.Errorf("ArchivesFilters.From invalid: %v", af.From)
+	}
+
+	if af.To != "2017-10-12T14:19:22.000000Z" {
+		t.Errorf("ArchivesFilters.To invalid: %v", af.To)
+	}
+
+	if err := af.Validate(); err != nil {
+		t.Errorf("ArchivesFilters should be valid: %v", err)
+	}
+
+	af.ToTime(time.Date(2017, 10, 12, 14, 19, 20, 0, time.UTC))
+	if err := af.Validate(); err == nil {
+		t.Errorf("ArchivesFilters with from after to should be rejected")
+	}
+}
+
+func TestDateRangeFilterValidate(t *testing.T) {
+	t1 := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	t2 := t1.Add(time.Hour)
+
+	for _, tc := range []struct {
+		drf   *agent.DateRangeFilter
+		valid bool
+	}{
+		{agent.NewDateRangeFilter().Since(t1).Until(t2), true},
+		{agent.NewDateRangeFilter().Since(t1).Until(t1), true},
+		{agent.NewDateRangeFilter().After(t1).Until(t1), false},
+		{agent.NewDateRangeFilter().Since(t2).Before(t1), false},
+		{agent.NewDateRangeFilter().At(t1).Before(t2), true},
+		{agent.NewDateRangeFilter().At(t2).Before(t2), false},
+		{&agent.DateRangeFilter{GT: "11-09-2001", LT: "02-04-2137"}, true},
+	} {
+		if err := tc.drf.Validate(); (err == nil) != tc.valid {
+			t.Errorf("invalid validation result of %+v: %v", *tc.drf, err)
+		}
+	}
+}
+
+func TestCustomersFiltersValidate(t *testing.T) {
+	now := time.Now()
+	cf := agent.NewCustomersFilters().
+		ByCreationTime(agent.NewDateRangeFilter().Until(now)).
+		ByCustomersLastActivity(agent.NewDateRangeFilter().Since(now).Before(now.Add(-time.Minute)))
+
+	if err := cf.Validate(); err == nil {
+		t.Errorf("CustomersFilters with contradictory range should be rejected")
+	}
+}
+
+func TestTimeRanges(t *testing.T) {
+	loc := time.FixedZone("UTC-3", -3*60*60)
+	// Sunday, 23:00 in UTC-3.
+	now := time.Date(2020, 1, 13, 2, 0, 0, 0, time.UTC)
+
+	last := agent.LastNDays(3, now, loc)
+	if expected := time.Date(2020, 1, 10, 0, 0, 0, 0, loc); !last.From.Equal(expected) || !last.To.Equal(now) {
+		t.Errorf("LastNDays invalid: %v", last)
+	}
+
+	week := agent.ThisWeek(now, loc)
+	if expected := time.Date(2020, 1, 6, 0, 0, 0, 0, loc); !week.From.Equal(expected) || !week.To.Equal(now) {
+		t.Errorf("ThisWeek invalid: %v", week)
+	}
+	if err := week.Validate(); err != nil {
+		t.Errorf("TimeRange should be valid: %v", err)
+	}
+
+	drf := week.DateRangeFilter()
+	if drf.GTE != "2020-01-06T00:00:00.000000-03:00" || drf.LTE != "2020-01-12T23:00:00.000000-03:00" {
+		t.Errorf("DateRangeFilter invalid: %+v", drf)
+	}
+}
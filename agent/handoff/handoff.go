@@ -0,0 +1,153 @@
+// Package handoff orchestrates transfers of chats between agents and groups.
+//
+// Handoff tries a chain of selection strategies in order. Each strategy picks a transfer Target, ie. an agent
+// chosen from agents available for transfer or a group. When a transfer fails, next strategy is tried, skipping
+// agents which already failed. After a successful transfer the handoff is announced with a system message.
+package handoff
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/livechat/lc-sdk-go/v2/agent"
+	"github.com/livechat/lc-sdk-go/v2/objects"
+)
+
+// API is a subset of agent.API used by Handoff.
+type API interface {
+	ListAgentsForTransfer(chatID string) (agent.AgentsForTransfer, error)
+	TransferChat(chatID, targetType string, ids []interface{}, force bool) error
+	SendEvent(chatID string, event interface{}, attachToLastThread bool) (string, error)
+}
+
+// Target describes where a chat is transferred to.
+type Target struct {
+	// Type is either "agent", "group" or empty for transfer within chat's current group.
+	Type string
+	IDs  []interface{}
+}
+
+// Agents creates Target transferring chat to given agents.
+func Agents(ids ...string) Target {
+	t := Target{Type: "agent"}
+	for _, id := range ids {
+		t.IDs = append(t.IDs, id)
+	}
+	return t
+}
+
+// Groups creates Target transferring chat to given groups.
+func Groups(ids ...int) Target {
+	t := Target{Type: "group"}
+	for _, id := range ids {
+		t.IDs = append(t.IDs, id)
+	}
+	return t
+}
+
+// CurrentGroup creates Target transferring chat to any agent of chat's current group.
+func CurrentGroup() Target {
+	return Target{}
+}
+
+func (t Target) String() string {
+	if t.Type == "" {
+		return "current group"
+	}
+	ids := make([]string, 0, len(t.IDs))
+	for _, id := range t.IDs {
+		ids = append(ids, fmt.Sprintf("%v", id))
+	}
+	return t.Type + " " + strings.Join(ids, ", ")
+}
+
+// ErrNoCandidate is returned by strategies which cannot choose any target.
+var ErrNoCandidate = errors.New("no transfer candidate")
+
+// AnnouncementType is system message type of handoff announcements.
+const AnnouncementType = "handoff"
+
+// Handoff transfers chats using chain of strategies.
+type Handoff struct {
+	api          API
+	strategies   []Strategy
+	force        bool
+	announcement string
+}
+
+// New creates Handoff trying given strategies in order.
+func New(api API, strategies ...Strategy) *Handoff {
+	return &Handoff{
+		api:        api,
+		strategies: strategies,
+	}
+}
+
+// WithForce makes Handoff transfer chats even if target agents are not accepting chats.
+func (h *Handoff) WithForce(force bool) *Handoff {
+	h.force = force
+	return h
+}
+
+// WithAnnouncement sets text of system message sent to the chat after successful transfer.
+// Announcement is not sent if text is empty.
+func (h *Handoff) WithAnnouncement(text string) *Handoff {
+	h.announcement = text
+	return h
+}
+
+// Transfer transfers given chat to target chosen by the first successful strategy.
+// It returns the target and, if no strategy succeeded, error describing all failures.
+func (h *Handoff) Transfer(chatID string) (Target, error) {
+	var (
+		candidates agent.AgentsForTransfer
+		listed     bool
+		failed     = make(map[string]bool)
+		failures   []string
+	)
+
+	for i, s := range h.strategies {
+		if !listed {
+			var err error
+			if candidates, err = h.api.ListAgentsForTransfer(chatID); err != nil {
+				return Target{}, fmt.Errorf("couldn't list agents for transfer: %v", err)
+			}
+			listed = true
+		}
+
+		available := make(agent.AgentsForTransfer, 0, len(candidates))
+		for _, c := range candidates {
+			if !failed[c.AgentID] {
+				available = append(available, c)
+			}
+		}
+
+		target, err := s.Select(chatID, available)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("strategy #%d: %v", i+1, err))
+			continue
+		}
+		if err := h.api.TransferChat(chatID, target.Type, target.IDs, h.force); err != nil {
+			failures = append(failures, fmt.Sprintf("strategy #%d: transfer to %v failed: %v", i+1, target, err))
+			if target.Type == "agent" {
+				for _, id := range target.IDs {
+					failed[fmt.Sprintf("%v", id)] = true
+				}
+			}
+			continue
+		}
+
+		if h.announcement != "" {
+			if _, err := h.api.SendEvent(chatID, objects.NewSystemMessage(h.announcement, AnnouncementType), true); err != nil {
+				return target, fmt.Errorf("chat transferred to %v, but announcement failed: %v", target, err)
+			}
+		}
+		return target, nil
+	}
+
+	if len(failures) == 0 {
+		return Target{}, errors.New("handoff failed: no strategies")
+	}
+	return Target{}, fmt.Errorf("handoff failed: %s", strings.Join(failures, "; "))
+}
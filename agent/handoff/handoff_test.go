@@ -0,0 +1,126 @@
+package handoff_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/livechat/lc-sdk-go/v2/agent"
+	"github.com/livechat/lc-sdk-go/v2/agent/handoff"
+	"github.com/livechat/lc-sdk-go/v2/objects"
+)
+
+var _ handoff.API = &agent.API{}
+
+type transfer struct {
+	targetType string
+	ids        []interface{}
+}
+
+type fakeAPI struct {
+	candidates agent.AgentsForTransfer
+	failing    map[interface{}]bool
+	transfers  []transfer
+	sent       []interface{}
+}
+
+func (f *fakeAPI) ListAgentsForTransfer(chatID string) (agent.AgentsForTransfer, error) {
+	return f.candidates, nil
+}
+
+func (f *fakeAPI) TransferChat(chatID, targetType string, ids []interface{}, force bool) error {
+	f.transfers = append(f.transfers, transfer{targetType, ids})
+	for _, id := range ids {
+		if f.failing[id] {
+			return errors.New("agent not accepting chats")
+		}
+	}
+	return nil
+}
+
+func (f *fakeAPI) SendEvent(chatID string, event interface{}, attachToLastThread bool) (string, error) {
+	f.sent = append(f.sent, event)
+	return "event_id", nil
+}
+
+func newFakeAPI() *fakeAPI {
+	return &fakeAPI{
+		candidates: agent.AgentsForTransfer{
+			{AgentID: "b@example.com", TotalActiveChats: 3},
+			{AgentID: "a@example.com", TotalActiveChats: 1},
+			{AgentID: "c@example.com", TotalActiveChats: 1},
+		},
+		failing: make(map[interface{}]bool),
+	}
+}
+
+func TestLeastBusyShouldChooseAgentWithFewestChats(t *testing.T) {
+	api := newFakeAPI()
+
+	target, err := handoff.New(api, handoff.LeastBusy()).Transfer("chat")
+	if err != nil {
+		t.Fatalf("Transfer failed: %v", err)
+	}
+	if target.Type != "agent" || target.IDs[0] != "a@example.com" {
+		t.Errorf("invalid target: %v", target)
+	}
+}
+
+func TestRoundRobinShouldRotateAgents(t *testing.T) {
+	api := newFakeAPI()
+	h := handoff.New(api, handoff.RoundRobin())
+
+	expected := []string{"a@example.com", "b@example.com", "c@example.com", "a@example.com"}
+	for _, id := range expected {
+		if target, err := h.Transfer("chat"); err != nil || target.IDs[0] != id {
+			t.Errorf("invalid target: %v, %v, expected: %v", target, err, id)
+		}
+	}
+}
+
+func TestSkillBasedShouldChooseGroup(t *testing.T) {
+	api := newFakeAPI()
+	s := handoff.SkillBased(map[string]int{"billing": 2}, func(chatID string) (string, error) {
+		return "billing", nil
+	})
+
+	target, err := handoff.New(api, s).Transfer("chat")
+	if err != nil || target.Type != "group" || target.IDs[0] != 2 {
+		t.Errorf("invalid target: %v, %v", target, err)
+	}
+}
+
+func TestHandoffShouldFallBackAndSkipFailedAgents(t *testing.T) {
+	api := newFakeAPI()
+	api.failing["a@example.com"] = true
+
+	target, err := handoff.New(api, handoff.LeastBusy(), handoff.LeastBusy(), handoff.Fixed(handoff.Groups(0))).
+		WithAnnouncement("Transferring you to a specialist").
+		Transfer("chat")
+	if err != nil {
+		t.Fatalf("Transfer failed: %v", err)
+	}
+	if target.IDs[0] != "c@example.com" || len(api.transfers) != 2 {
+		t.Errorf("invalid target: %v, transfers: %v", target, api.transfers)
+	}
+	if m, ok := api.sent[0].(*objects.SystemMessage); len(api.sent) != 1 || !ok || m.Text != "Transferring you to a specialist" || m.Type != handoff.AnnouncementType {
+		t.Errorf("invalid announcement: %v", api.sent)
+	}
+}
+
+func TestHandoffShouldReportAllFailures(t *testing.T) {
+	api := newFakeAPI()
+	api.candidates = nil
+	api.failing[1] = true
+
+	_, err := handoff.New(api, handoff.LeastBusy(), handoff.Fixed(handoff.Groups(1))).Transfer("chat")
+	if err == nil {
+		t.Fatalf("Transfer should fail")
+	}
+	expected := "handoff failed: strategy #1: no transfer candidate; strategy #2: transfer to group 1 failed: agent not accepting chats"
+	if err.Error() != expected {
+		t.Errorf("invalid error: %v", err)
+	}
+	if len(api.sent) != 0 {
+		t.Errorf("announcement should not be sent")
+	}
+}
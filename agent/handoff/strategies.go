@@ -0,0 +1,100 @@
+package handoff
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/livechat/lc-sdk-go/v2/agent"
+)
+
+// Strategy chooses transfer Target for a chat from agents available for transfer.
+type Strategy interface {
+	Select(chatID string, candidates agent.AgentsForTransfer) (Target, error)
+}
+
+// StrategyFunc is an adapter allowing to use ordinary functions as Strategy.
+type StrategyFunc func(chatID string, candidates agent.AgentsForTransfer) (Target, error)
+
+// Select implements Strategy.
+func (f StrategyFunc) Select(chatID string, candidates agent.AgentsForTransfer) (Target, error) {
+	return f(chatID, candidates)
+}
+
+// Fixed returns Strategy always choosing given target.
+func Fixed(target Target) Strategy {
+	return StrategyFunc(func(chatID string, candidates agent.AgentsForTransfer) (Target, error) {
+		return target, nil
+	})
+}
+
+// LeastBusy returns Strategy choosing agent with the lowest number of active chats.
+// Ties are resolved in favor of agent listed first.
+func LeastBusy() Strategy {
+	return StrategyFunc(func(chatID string, candidates agent.AgentsForTransfer) (Target, error) {
+		if len(candidates) == 0 {
+			return Target{}, ErrNoCandidate
+		}
+		best := 0
+		for i, c := range candidates {
+			if c.TotalActiveChats < candidates[best].TotalActiveChats {
+				best = i
+			}
+		}
+		return Agents(candidates[best].AgentID), nil
+	})
+}
+
+type roundRobin struct {
+	mu   sync.Mutex
+	last string
+}
+
+// RoundRobin returns Strategy choosing available agents in turns, ordered by their IDs.
+func RoundRobin() Strategy {
+	return &roundRobin{}
+}
+
+// Select implements Strategy.
+func (r *roundRobin) Select(chatID string, candidates agent.AgentsForTransfer) (Target, error) {
+	if len(candidates) == 0 {
+		return Target{}, ErrNoCandidate
+	}
+	ids := make([]string, 0, len(candidates))
+	for _, c := range candidates {
+		ids = append(ids, c.AgentID)
+	}
+	sort.Strings(ids)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	next := ids[0]
+	for _, id := range ids {
+		if id > r.last {
+			next = id
+			break
+		}
+	}
+	r.last = next
+	return Agents(next), nil
+}
+
+// SkillResolver returns skill needed to handle given chat.
+type SkillResolver func(chatID string) (string, error)
+
+// SkillBased returns Strategy transferring chat to group handling skill needed by the chat.
+// Groups are given as mapping of skills to group IDs.
+func SkillBased(groups map[string]int, resolve SkillResolver) Strategy {
+	return StrategyFunc(func(chatID string, candidates agent.AgentsForTransfer) (Target, error) {
+		skill, err := resolve(chatID)
+		if err != nil {
+			return Target{}, fmt.Errorf("couldn't resolve skill: %v", err)
+		}
+		groupID, exists := groups[skill]
+		if !exists {
+			return Target{}, fmt.Errorf("no group for skill: %q", skill)
+		}
+		return Groups(groupID), nil
+	})
+}
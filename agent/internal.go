@@ -182,10 +182,10 @@ type listCustomersResponse struct {
 }
 
 type createCustomerRequest struct {
-	Name          string              `json:"name,omitempty"`
-	Email         string              `json:"email,omitempty"`
-	Avatar        string              `json:"avatar,omitempty"`
-	SessionFields []map[string]string `json:"session_fields,omitempty"`
+	Name          string                `json:"name,omitempty"`
+	Email         string                `json:"email,omitempty"`
+	Avatar        string                `json:"avatar,omitempty"`
+	SessionFields objects.SessionFields `json:"session_fields,omitempty"`
 }
 
 type createCustomerResponse struct {
@@ -193,11 +193,11 @@ type createCustomerResponse struct {
 }
 
 type updateCustomerRequest struct {
-	CustomerID    string              `json:"customer_id"`
-	Name          string              `json:"name,omitempty"`
-	Email         string              `json:"email,omitempty"`
-	Avatar        string              `json:"avatar,omitempty"`
-	SessionFields []map[string]string `json:"session_fields,omitempty"`
+	CustomerID    string                `json:"customer_id"`
+	Name          string                `json:"name,omitempty"`
+	Email         string                `json:"email,omitempty"`
+	Avatar        string                `json:"avatar,omitempty"`
+	SessionFields objects.SessionFields `json:"session_fields,omitempty"`
 }
 
 type banCustomerRequest struct {
@@ -206,8 +206,8 @@ type banCustomerRequest struct {
 }
 
 type setRoutingStatusRequest struct {
-	AgentID string `json:"agent_id,omitempty"`
-	Status  string `json:"status,omitempty"`
+	AgentID string                `json:"agent_id,omitempty"`
+	Status  objects.RoutingStatus `json:"status,omitempty"`
 }
 
 type markEventsAsSeenRequest struct {
@@ -0,0 +1,159 @@
+// Package routing switches routing status of agents and bots according to their work schedules.
+package routing
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/livechat/lc-sdk-go/v2/configuration"
+	"github.com/livechat/lc-sdk-go/v2/objects"
+)
+
+// API is a subset of agent.API used by Scheduler.
+type API interface {
+	SetRoutingStatus(agentID string, status objects.RoutingStatus) error
+}
+
+// Scheduler sets routing status of agents (or bots) depending on whether they are on duty according
+// to their configuration.WorkScheduler.
+type Scheduler struct {
+	api      API
+	location *time.Location
+	onDuty   objects.RoutingStatus
+	offDuty  objects.RoutingStatus
+	now      func() time.Time
+
+	mu        sync.Mutex
+	schedules map[string]configuration.WorkScheduler
+	current   map[string]objects.RoutingStatus
+}
+
+// NewScheduler creates Scheduler evaluating schedules in given location. By default, agents on duty
+// are accepting chats, while the others are not accepting chats.
+func NewScheduler(api API, location *time.Location) *Scheduler {
+	return &Scheduler{
+		api:       api,
+		location:  location,
+		onDuty:    objects.AcceptingChats,
+		offDuty:   objects.NotAcceptingChats,
+		now:       time.Now,
+		schedules: make(map[string]configuration.WorkScheduler),
+		current:   make(map[string]objects.RoutingStatus),
+	}
+}
+
+// WithStatuses sets routing statuses of agents on and off duty.
+func (s *Scheduler) WithStatuses(onDuty, offDuty objects.RoutingStatus) *Scheduler {
+	s.onDuty = onDuty
+	s.offDuty = offDuty
+	return s
+}
+
+// WithClock replaces function used by Scheduler to get current time.
+func (s *Scheduler) WithClock(now func() time.Time) *Scheduler {
+	s.now = now
+	return s
+}
+
+// Add sets schedule of given agent.
+func (s *Scheduler) Add(agentID string, schedule configuration.WorkScheduler) *Scheduler {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.schedules[agentID] = schedule
+	delete(s.current, agentID)
+	return s
+}
+
+// Remove stops managing routing status of given agent.
+func (s *Scheduler) Remove(agentID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.schedules, agentID)
+	delete(s.current, agentID)
+}
+
+// Status returns routing status given agent should have at time t.
+func (s *Scheduler) Status(agentID string, t time.Time) (objects.RoutingStatus, error) {
+	s.mu.Lock()
+	schedule, exists := s.schedules[agentID]
+	s.mu.Unlock()
+
+	if !exists {
+		return "", fmt.Errorf("no schedule for agent: %q", agentID)
+	}
+	onDuty, err := schedule.OnDuty(t, s.location)
+	if err != nil {
+		return "", err
+	}
+	if onDuty {
+		return s.onDuty, nil
+	}
+	return s.offDuty, nil
+}
+
+// Sync sets routing status of all scheduled agents whose status should change since the last Sync.
+// Failed agents are retried on the next Sync.
+func (s *Scheduler) Sync() error {
+	s.mu.Lock()
+	ids := make([]string, 0, len(s.schedules))
+	for id := range s.schedules {
+		ids = append(ids, id)
+	}
+	s.mu.Unlock()
+	sort.Strings(ids)
+
+	now := s.now()
+	var failures []string
+	for _, id := range ids {
+		status, err := s.Status(id, now)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", id, err))
+			continue
+		}
+
+		s.mu.Lock()
+		unchanged := s.current[id] == status
+		s.mu.Unlock()
+		if unchanged {
+			continue
+		}
+
+		if err := s.api.SetRoutingStatus(id, status); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", id, err))
+			continue
+		}
+		s.mu.Lock()
+		if _, scheduled := s.schedules[id]; scheduled {
+			s.current[id] = status
+		}
+		s.mu.Unlock()
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("couldn't set routing status: %s", strings.Join(failures, "; "))
+	}
+	return nil
+}
+
+// Run calls Sync every interval until ctx is done. Sync errors are passed to onError, if not nil.
+func (s *Scheduler) Run(ctx context.Context, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Sync(); err != nil && onError != nil {
+			onError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
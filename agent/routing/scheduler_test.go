@@ -0,0 +1,80 @@
+package routing_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/livechat/lc-sdk-go/v2/agent"
+	"github.com/livechat/lc-sdk-go/v2/agent/routing"
+	"github.com/livechat/lc-sdk-go/v2/configuration"
+	"github.com/livechat/lc-sdk-go/v2/objects"
+)
+
+var _ routing.API = &agent.API{}
+
+type fakeAPI struct {
+	calls   map[string][]objects.RoutingStatus
+	failing bool
+}
+
+func (f *fakeAPI) SetRoutingStatus(agentID string, status objects.RoutingStatus) error {
+	if f.failing {
+		return errors.New("service unavailable")
+	}
+	f.calls[agentID] = append(f.calls[agentID], status)
+	return nil
+}
+
+var schedule = configuration.WorkScheduler{
+	configuration.Monday: {Start: "09:00", End: "17:00"},
+}
+
+func TestSchedulerShouldSetStatusAccordingToSchedule(t *testing.T) {
+	loc := time.FixedZone("UTC+2", 2*60*60)
+	// Monday, 10:00 in UTC+2.
+	now := time.Date(2020, 1, 6, 8, 0, 0, 0, time.UTC)
+	api := &fakeAPI{calls: make(map[string][]objects.RoutingStatus)}
+	s := routing.NewScheduler(api, loc).
+		WithClock(func() time.Time { return now }).
+		Add("agent@example.com", schedule).
+		Add("bot", configuration.WorkScheduler{})
+
+	if err := s.Sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if err := s.Sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	now = now.Add(8 * time.Hour)
+	if err := s.Sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+
+	if c := api.calls["agent@example.com"]; len(c) != 2 || c[0] != objects.AcceptingChats || c[1] != objects.NotAcceptingChats {
+		t.Errorf("invalid agent status changes: %v", c)
+	}
+	if c := api.calls["bot"]; len(c) != 1 || c[0] != objects.NotAcceptingChats {
+		t.Errorf("invalid bot status changes: %v", c)
+	}
+}
+
+func TestSchedulerShouldRetryFailedChanges(t *testing.T) {
+	api := &fakeAPI{calls: make(map[string][]objects.RoutingStatus), failing: true}
+	s := routing.NewScheduler(api, time.UTC).
+		WithStatuses(objects.AcceptingChats, objects.Offline).
+		WithClock(func() time.Time { return time.Date(2020, 1, 7, 8, 0, 0, 0, time.UTC) }).
+		Add("agent@example.com", schedule)
+
+	if err := s.Sync(); err == nil {
+		t.Errorf("Sync should fail")
+	}
+	api.failing = false
+	if err := s.Sync(); err != nil {
+		t.Errorf("Sync failed: %v", err)
+	}
+
+	if c := api.calls["agent@example.com"]; len(c) != 1 || c[0] != objects.Offline {
+		t.Errorf("invalid agent status changes: %v", c)
+	}
+}
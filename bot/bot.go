@@ -0,0 +1,324 @@
+// Package bot provides a framework for building LiveChat bots.
+//
+// A Bot is declared with its name, avatar and group assignments, provisioned via Configuration API
+// and reacts on chat events passed to conversation handlers (OnMessage, OnPostback, OnChatStarted and
+// OnChatDeactivated). Handlers receive Context, which acts in the chat on behalf of the bot.
+//
+// General LiveChat bots documentation is available here:
+// https://developers.livechatinc.com/docs/extending-chat-widget/bots/
+package bot
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/livechat/lc-sdk-go/v2/configuration"
+	"github.com/livechat/lc-sdk-go/v2/objects"
+	"github.com/livechat/lc-sdk-go/v2/webhooks"
+)
+
+// AgentAPI is a subset of agent.API used by bots to act in chats.
+type AgentAPI interface {
+	SetAuthorID(authorID string)
+	SendEvent(chatID string, event interface{}, attachToLastThread bool) (string, error)
+	SetRoutingStatus(agentID string, status objects.RoutingStatus) error
+	DeactivateChat(chatID string) error
+	TransferChat(chatID, targetType string, ids []interface{}, force bool) error
+}
+
+// AgentAPIFactory returns AgentAPI authorized to act within given license.
+//
+// It is called for every handled webhook, so it should reuse APIs instead of creating new ones each time.
+// Returned API is used exclusively by the bot, as its author ID is set to bot's ID.
+type AgentAPIFactory func(licenseID int) (AgentAPI, error)
+
+// ConfigurationAPI is a subset of configuration.API used to provision bots.
+type ConfigurationAPI interface {
+	ListBots(getAll bool) ([]*configuration.BotAgent, error)
+	CreateBot(name, avatar string, status configuration.BotStatus, maxChats uint, defaultPriority configuration.GroupPriority, groups []*configuration.GroupConfig, webhooks *configuration.BotWebhooks) (string, error)
+	UpdateBot(id, name, avatar string, status configuration.BotStatus, maxChats uint, defaultPriority configuration.GroupPriority, groups []*configuration.GroupConfig, webhooks *configuration.BotWebhooks) error
+}
+
+// MessageHandler handles messages sent by other chat users.
+type MessageHandler func(ctx *Context, message *objects.Message) error
+
+// PostbackHandler handles postbacks of rich messages.
+type PostbackHandler func(ctx *Context, postbackID string, toggled bool) error
+
+// ChatHandler handles chat lifecycle events.
+type ChatHandler func(ctx *Context) error
+
+// Bot represents declaration of a LiveChat bot along with its conversation handlers.
+type Bot struct {
+	Name                 string
+	Avatar               string
+	MaxChats             uint
+	DefaultGroupPriority configuration.GroupPriority
+	Groups               []*configuration.GroupConfig
+	WebhookURL           string
+	SecretKey            string
+
+	id                string
+	newAgentAPI       AgentAPIFactory
+	onMessage         MessageHandler
+	onPostback        PostbackHandler
+	onChatStarted     ChatHandler
+	onChatDeactivated ChatHandler
+}
+
+// New creates Bot with given name, which uses newAgentAPI to act in chats.
+//
+// By default, bot has normal priority in all groups.
+func New(name string, newAgentAPI AgentAPIFactory) *Bot {
+	return &Bot{
+		Name:                 name,
+		DefaultGroupPriority: configuration.Normal,
+		newAgentAPI:          newAgentAPI,
+	}
+}
+
+// WithID sets ID of already provisioned bot.
+func (b *Bot) WithID(id string) *Bot {
+	b.id = id
+	return b
+}
+
+// WithAvatar sets bot's avatar URL.
+func (b *Bot) WithAvatar(avatar string) *Bot {
+	b.Avatar = avatar
+	return b
+}
+
+// WithMaxChats sets maximum number of concurrent chats of the bot.
+func (b *Bot) WithMaxChats(maxChats uint) *Bot {
+	b.MaxChats = maxChats
+	return b
+}
+
+// WithDefaultGroupPriority sets bot's priority in groups it is not explicitly assigned to.
+func (b *Bot) WithDefaultGroupPriority(priority configuration.GroupPriority) *Bot {
+	b.DefaultGroupPriority = priority
+	return b
+}
+
+// WithGroup assigns bot to given group with given priority.
+func (b *Bot) WithGroup(id uint, priority configuration.GroupPriority) *Bot {
+	b.Groups = append(b.Groups, &configuration.GroupConfig{ID: id, Priority: priority})
+	return b
+}
+
+// WithWebhook sets URL and secret key of webhooks sent to the bot.
+func (b *Bot) WithWebhook(url, secretKey string) *Bot {
+	b.WebhookURL = url
+	b.SecretKey = secretKey
+	return b
+}
+
+// OnMessage sets handler of messages sent to the chat by other users.
+func (b *Bot) OnMessage(h MessageHandler) *Bot {
+	b.onMessage = h
+	return b
+}
+
+// OnPostback sets handler of rich messages' postbacks.
+func (b *Bot) OnPostback(h PostbackHandler) *Bot {
+	b.onPostback = h
+	return b
+}
+
+// OnChatStarted sets handler of chats started with the bot.
+func (b *Bot) OnChatStarted(h ChatHandler) *Bot {
+	b.onChatStarted = h
+	return b
+}
+
+// OnChatDeactivated sets handler of deactivated chats.
+func (b *Bot) OnChatDeactivated(h ChatHandler) *Bot {
+	b.onChatDeactivated = h
+	return b
+}
+
+// ID returns bot's ID. It is empty until the bot is provisioned or its ID is set with WithID.
+func (b *Bot) ID() string {
+	return b.id
+}
+
+// Actions returns webhook actions needed by registered conversation handlers.
+func (b *Bot) Actions() []configuration.WebhookAction {
+	var actions []configuration.WebhookAction
+	if b.onChatStarted != nil {
+		actions = append(actions, configuration.IncomingChat)
+	}
+	if b.onMessage != nil {
+		actions = append(actions, configuration.IncomingEvent)
+	}
+	if b.onPostback != nil {
+		actions = append(actions, configuration.IncomingRichMessagePostback)
+	}
+	if b.onChatDeactivated != nil {
+		actions = append(actions, configuration.ChatDeactivated)
+	}
+	return actions
+}
+
+// Webhooks returns configuration of bot's webhooks or nil if bot's webhook URL is not set.
+func (b *Bot) Webhooks() *configuration.BotWebhooks {
+	if b.WebhookURL == "" {
+		return nil
+	}
+	w := &configuration.BotWebhooks{
+		URL:       b.WebhookURL,
+		SecretKey: b.SecretKey,
+	}
+	for _, action := range b.Actions() {
+		w.Actions = append(w.Actions, &configuration.BotWebhookAction{Name: action})
+	}
+	return w
+}
+
+// Provision creates the bot or, if the application already owns a bot with the same name,
+// updates it to match the declaration. It returns bot's ID.
+func (b *Bot) Provision(api ConfigurationAPI) (string, error) {
+	if b.id == "" {
+		bots, err := api.ListBots(false)
+		if err != nil {
+			return "", fmt.Errorf("couldn't list bots: %v", err)
+		}
+		for _, bot := range bots {
+			if bot.Name == b.Name {
+				b.id = bot.ID
+				break
+			}
+		}
+	}
+
+	if b.id == "" {
+		id, err := api.CreateBot(b.Name, b.Avatar, "", b.MaxChats, b.DefaultGroupPriority, b.Groups, b.Webhooks())
+		if err != nil {
+			return "", fmt.Errorf("couldn't create bot: %v", err)
+		}
+		b.id = id
+		return id, nil
+	}
+
+	if err := api.UpdateBot(b.id, b.Name, b.Avatar, "", b.MaxChats, b.DefaultGroupPriority, b.Groups, b.Webhooks()); err != nil {
+		return "", fmt.Errorf("couldn't update bot: %v", err)
+	}
+	return b.id, nil
+}
+
+// SetRoutingStatus sets bot's routing status within given license.
+func (b *Bot) SetRoutingStatus(licenseID int, status objects.RoutingStatus) error {
+	api, err := b.agentAPI(licenseID)
+	if err != nil {
+		return err
+	}
+	return api.SetRoutingStatus(b.id, status)
+}
+
+func (b *Bot) agentAPI(licenseID int) (AgentAPI, error) {
+	if b.id == "" {
+		return nil, fmt.Errorf("bot %q is not provisioned", b.Name)
+	}
+	api, err := b.newAgentAPI(licenseID)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't create agent API: %v", err)
+	}
+	api.SetAuthorID(b.id)
+	return api, nil
+}
+
+// Configure attaches bot's conversation handlers to given webhooks Configuration.
+func (b *Bot) Configure(cfg *webhooks.Configuration) *webhooks.Configuration {
+	if b.onChatStarted != nil {
+		cfg.WithActionContext(string(configuration.IncomingChat), b.handleIncomingChat, b.SecretKey)
+	}
+	if b.onMessage != nil {
+		cfg.WithActionContext(string(configuration.IncomingEvent), b.handleIncomingEvent, b.SecretKey)
+	}
+	if b.onPostback != nil {
+		cfg.WithActionContext(string(configuration.IncomingRichMessagePostback), b.handlePostback, b.SecretKey)
+	}
+	if b.onChatDeactivated != nil {
+		cfg.WithActionContext(string(configuration.ChatDeactivated), b.handleChatDeactivated, b.SecretKey)
+	}
+	return cfg
+}
+
+// Handler returns http.Handler processing bot's webhooks.
+func (b *Bot) Handler() http.HandlerFunc {
+	return webhooks.NewWebhookHandler(b.Configure(webhooks.NewConfiguration()))
+}
+
+func (b *Bot) newContext(ctx context.Context, wh *webhooks.Webhook, chatID, threadID, userID string) (*Context, error) {
+	api, err := b.agentAPI(wh.LicenseID)
+	if err != nil {
+		return nil, err
+	}
+	return &Context{
+		Context:  ctx,
+		Webhook:  wh,
+		Bot:      b,
+		ChatID:   chatID,
+		ThreadID: threadID,
+		UserID:   userID,
+		api:      api,
+	}, nil
+}
+
+func (b *Bot) handleIncomingChat(ctx context.Context, wh *webhooks.Webhook) error {
+	payload, ok := wh.Payload.(*webhooks.IncomingChat)
+	if !ok {
+		return fmt.Errorf("invalid payload type: %T", wh.Payload)
+	}
+	c, err := b.newContext(ctx, wh, payload.Chat.ID, payload.Chat.Thread.ID, "")
+	if err != nil {
+		return err
+	}
+	return b.onChatStarted(c)
+}
+
+func (b *Bot) handleIncomingEvent(ctx context.Context, wh *webhooks.Webhook) error {
+	payload, ok := wh.Payload.(*webhooks.IncomingEvent)
+	if !ok {
+		return fmt.Errorf("invalid payload type: %T", wh.Payload)
+	}
+	// Bot receives its own events too - those are not passed to handlers.
+	if payload.Event.AuthorID == b.id {
+		return nil
+	}
+	message := payload.Event.Message()
+	if message == nil {
+		return nil
+	}
+	c, err := b.newContext(ctx, wh, payload.ChatID, payload.ThreadID, payload.Event.AuthorID)
+	if err != nil {
+		return err
+	}
+	return b.onMessage(c, message)
+}
+
+func (b *Bot) handlePostback(ctx context.Context, wh *webhooks.Webhook) error {
+	payload, ok := wh.Payload.(*webhooks.IncomingRichMessagePostback)
+	if !ok {
+		return fmt.Errorf("invalid payload type: %T", wh.Payload)
+	}
+	c, err := b.newContext(ctx, wh, payload.ChatID, payload.ThreadID, payload.UserID)
+	if err != nil {
+		return err
+	}
+	return b.onPostback(c, payload.Postback.ID, payload.Postback.Toggled)
+}
+
+func (b *Bot) handleChatDeactivated(ctx context.Context, wh *webhooks.Webhook) error {
+	payload, ok := wh.Payload.(*webhooks.ChatDeactivated)
+	if !ok {
+		return fmt.Errorf("invalid payload type: %T", wh.Payload)
+	}
+	c, err := b.newContext(ctx, wh, payload.ChatID, payload.ThreadID, payload.UserID)
+	if err != nil {
+		return err
+	}
+	return b.onChatDeactivated(c)
+}
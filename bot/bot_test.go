@@ -0,0 +1,184 @@
+package bot_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/livechat/lc-sdk-go/v2/agent"
+	"github.com/livechat/lc-sdk-go/v2/bot"
+	"github.com/livechat/lc-sdk-go/v2/configuration"
+	"github.com/livechat/lc-sdk-go/v2/objects"
+	"github.com/livechat/lc-sdk-go/v2/webhooks"
+	"github.com/livechat/lc-sdk-go/v2/webhooks/webhooktest"
+)
+
+var (
+	_ bot.AgentAPI         = &agent.API{}
+	_ bot.ConfigurationAPI = &configuration.API{}
+)
+
+type sentEvent struct {
+	chatID string
+	event  interface{}
+}
+
+type fakeAgentAPI struct {
+	authorID       string
+	sent           []sentEvent
+	routingStatus  map[string]objects.RoutingStatus
+	deactivated    []string
+	transferTarget []interface{}
+}
+
+func (f *fakeAgentAPI) SetAuthorID(authorID string) {
+	f.authorID = authorID
+}
+
+func (f *fakeAgentAPI) SendEvent(chatID string, event interface{}, attachToLastThread bool) (string, error) {
+	f.sent = append(f.sent, sentEvent{chatID, event})
+	return "event_id", nil
+}
+
+func (f *fakeAgentAPI) SetRoutingStatus(agentID string, status objects.RoutingStatus) error {
+	f.routingStatus[agentID] = status
+	return nil
+}
+
+func (f *fakeAgentAPI) DeactivateChat(chatID string) error {
+	f.deactivated = append(f.deactivated, chatID)
+	return nil
+}
+
+func (f *fakeAgentAPI) TransferChat(chatID, targetType string, ids []interface{}, force bool) error {
+	f.transferTarget = ids
+	return nil
+}
+
+func newFakeAgentAPI() *fakeAgentAPI {
+	return &fakeAgentAPI{routingStatus: make(map[string]objects.RoutingStatus)}
+}
+
+type fakeConfigurationAPI struct {
+	bots    []*configuration.BotAgent
+	created *configuration.BotWebhooks
+	updated string
+}
+
+func (f *fakeConfigurationAPI) ListBots(getAll bool) ([]*configuration.BotAgent, error) {
+	return f.bots, nil
+}
+
+func (f *fakeConfigurationAPI) CreateBot(name, avatar string, status configuration.BotStatus, maxChats uint, defaultPriority configuration.GroupPriority, groups []*configuration.GroupConfig, webhooks *configuration.BotWebhooks) (string, error) {
+	f.created = webhooks
+	return "new_bot", nil
+}
+
+func (f *fakeConfigurationAPI) UpdateBot(id, name, avatar string, status configuration.BotStatus, maxChats uint, defaultPriority configuration.GroupPriority, groups []*configuration.GroupConfig, webhooks *configuration.BotWebhooks) error {
+	f.updated = id
+	return nil
+}
+
+func newTestBot(api *fakeAgentAPI) *bot.Bot {
+	return bot.New("Echo", func(licenseID int) (bot.AgentAPI, error) {
+		return api, nil
+	}).WithID("bot_id").WithWebhook("https://bot.example.com", "secret")
+}
+
+func TestProvisionShouldCreateBotWithWebhooksOfHandledActions(t *testing.T) {
+	confAPI := &fakeConfigurationAPI{}
+	b := newTestBot(newFakeAgentAPI()).WithID("").
+		OnMessage(func(ctx *bot.Context, m *objects.Message) error { return nil }).
+		OnChatDeactivated(func(ctx *bot.Context) error { return nil })
+
+	id, err := b.Provision(confAPI)
+	if err != nil {
+		t.Fatalf("Provision failed: %v", err)
+	}
+	if id != "new_bot" || b.ID() != "new_bot" {
+		t.Errorf("invalid bot ID: %v", id)
+	}
+	w := confAPI.created
+	if w == nil || w.URL != "https://bot.example.com" || len(w.Actions) != 2 || w.Actions[0].Name != configuration.IncomingEvent || w.Actions[1].Name != configuration.ChatDeactivated {
+		t.Errorf("invalid bot webhooks: %+v", w)
+	}
+}
+
+func TestProvisionShouldUpdateExistingBot(t *testing.T) {
+	confAPI := &fakeConfigurationAPI{bots: []*configuration.BotAgent{{ID: "other", Name: "Other"}, {ID: "existing", Name: "Echo"}}}
+	b := newTestBot(newFakeAgentAPI()).WithID("")
+
+	if id, err := b.Provision(confAPI); err != nil || id != "existing" || confAPI.updated != "existing" {
+		t.Errorf("invalid provisioning result: %v, %v, %v", id, err, confAPI.updated)
+	}
+}
+
+func TestBotShouldReplyToMessagesAsBot(t *testing.T) {
+	api := newFakeAgentAPI()
+	b := newTestBot(api).OnMessage(func(ctx *bot.Context, m *objects.Message) error {
+		_, err := ctx.Reply("You said: " + m.Text)
+		return err
+	})
+	sim := webhooktest.NewSimulator(b.Handler(), 100, "secret")
+
+	payload := webhooktest.SamplePayload("incoming_event").(*webhooks.IncomingEvent)
+	sim.ExpectOK(t, "incoming_event", payload)
+
+	if api.authorID != "bot_id" {
+		t.Errorf("invalid author ID: %v", api.authorID)
+	}
+	if len(api.sent) != 1 || api.sent[0].chatID != payload.ChatID {
+		t.Fatalf("invalid sent events: %v", api.sent)
+	}
+	if m, ok := api.sent[0].event.(*objects.Message); !ok || m.Text != "You said: "+payload.Event.Message().Text {
+		t.Errorf("invalid reply: %+v", api.sent[0].event)
+	}
+}
+
+func TestBotShouldIgnoreOwnMessages(t *testing.T) {
+	api := newFakeAgentAPI()
+	b := newTestBot(api).OnMessage(func(ctx *bot.Context, m *objects.Message) error {
+		t.Errorf("handler should not be called for bot's own message")
+		return nil
+	})
+	sim := webhooktest.NewSimulator(b.Handler(), 100, "secret")
+
+	payload := webhooktest.SamplePayload("incoming_event").(*webhooks.IncomingEvent)
+	payload.Event.AuthorID = "bot_id"
+	sim.ExpectOK(t, "incoming_event", payload)
+}
+
+func TestBotShouldPassPostbacksAndChatEvents(t *testing.T) {
+	api := newFakeAgentAPI()
+	var postback string
+	b := newTestBot(api).
+		OnPostback(func(ctx *bot.Context, postbackID string, toggled bool) error {
+			postback = postbackID
+			return ctx.SetRoutingStatus(objects.NotAcceptingChats)
+		}).
+		OnChatStarted(func(ctx *bot.Context) error {
+			_, err := ctx.Reply("Hello!")
+			return err
+		}).
+		OnChatDeactivated(func(ctx *bot.Context) error {
+			return ctx.Deactivate()
+		})
+	sim := webhooktest.NewSimulator(b.Handler(), 100, "secret")
+
+	sim.ExpectOK(t, "incoming_rich_message_postback", webhooktest.SamplePayload("incoming_rich_message_postback"))
+	sim.ExpectOK(t, "incoming_chat", webhooktest.SamplePayload("incoming_chat"))
+	sim.ExpectOK(t, "chat_deactivated", webhooktest.SamplePayload("chat_deactivated"))
+
+	if postback != "action_yes" || api.routingStatus["bot_id"] != objects.NotAcceptingChats {
+		t.Errorf("invalid postback handling: %v, %v", postback, api.routingStatus)
+	}
+	if len(api.sent) != 1 || len(api.deactivated) != 1 {
+		t.Errorf("invalid chat events handling: %v, %v", api.sent, api.deactivated)
+	}
+}
+
+func TestBotShouldRejectWebhooksWithInvalidSecret(t *testing.T) {
+	b := newTestBot(newFakeAgentAPI()).OnChatStarted(func(ctx *bot.Context) error { return nil })
+	sim := webhooktest.NewSimulator(b.Handler(), 100, "invalid")
+
+	sim.Expect(t, "incoming_chat", webhooktest.SamplePayload("incoming_chat"), http.StatusBadRequest)
+}
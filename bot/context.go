@@ -0,0 +1,46 @@
+package bot
+
+import (
+	"context"
+
+	"github.com/livechat/lc-sdk-go/v2/objects"
+	"github.com/livechat/lc-sdk-go/v2/webhooks"
+)
+
+// Context is passed to conversation handlers and allows to act in the chat on behalf of the bot.
+type Context struct {
+	context.Context
+	Webhook  *webhooks.Webhook
+	Bot      *Bot
+	ChatID   string
+	ThreadID string
+	// UserID identifies user who triggered the webhook, if known.
+	UserID string
+
+	api AgentAPI
+}
+
+// API returns AgentAPI acting as the bot.
+func (c *Context) API() AgentAPI {
+	return c.api
+}
+
+// Reply sends message with given text to the chat. It returns event ID.
+func (c *Context) Reply(text string) (string, error) {
+	return c.Send(objects.NewMessage(text))
+}
+
+// Send sends given event to the chat. It returns event ID.
+func (c *Context) Send(event interface{}) (string, error) {
+	return c.api.SendEvent(c.ChatID, event, true)
+}
+
+// SetRoutingStatus sets bot's routing status.
+func (c *Context) SetRoutingStatus(status objects.RoutingStatus) error {
+	return c.api.SetRoutingStatus(c.Bot.ID(), status)
+}
+
+// Deactivate deactivates the chat.
+func (c *Context) Deactivate() error {
+	return c.api.DeactivateChat(c.ChatID)
+}
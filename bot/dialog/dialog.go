@@ -0,0 +1,186 @@
+// Package dialog implements multi-step bot dialogs.
+//
+// A Dialog is a declarative list of steps. Each step prompts the user (with plain text or quick replies),
+// validates the answer and stores it under step's name. Engine keeps track of dialogs in progress, keyed
+// by chat (or thread) ID, in a pluggable Storage - in memory, in files or in chat/thread properties.
+package dialog
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/livechat/lc-sdk-go/v2/bot"
+	"github.com/livechat/lc-sdk-go/v2/objects"
+)
+
+// Prompt describes question asked by a step.
+type Prompt struct {
+	Text string
+	// QuickReplies, if set, are sent as buttons of quick replies rich message.
+	QuickReplies []string
+}
+
+// Text creates Prompt sent as plain message.
+func Text(text string) Prompt {
+	return Prompt{Text: text}
+}
+
+// QuickReplies creates Prompt sent as quick replies rich message with given replies.
+func QuickReplies(text string, replies ...string) Prompt {
+	return Prompt{Text: text, QuickReplies: replies}
+}
+
+// Event returns event which should be sent to ask the Prompt.
+func (p Prompt) Event() interface{} {
+	if len(p.QuickReplies) == 0 {
+		return objects.NewMessage(p.Text)
+	}
+	buttons := make([]objects.RichMessageButton, 0, len(p.QuickReplies))
+	for _, reply := range p.QuickReplies {
+		buttons = append(buttons, objects.NewMessageButton(reply, reply))
+	}
+	return objects.NewQuickReplies(p.Text, buttons...)
+}
+
+// Validator checks user's answer. It returns normalized value which is stored in dialog's data.
+// Error message is sent to the user before the prompt is repeated.
+type Validator func(input string) (string, error)
+
+// Any accepts any non-empty answer.
+func Any(input string) (string, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return "", errors.New("Please provide an answer.")
+	}
+	return input, nil
+}
+
+// Email accepts valid email addresses.
+func Email(input string) (string, error) {
+	addr, err := mail.ParseAddress(strings.TrimSpace(input))
+	if err != nil {
+		return "", errors.New("Please provide a valid email address.")
+	}
+	return addr.Address, nil
+}
+
+// OneOf creates Validator accepting given options, case insensitive.
+func OneOf(options ...string) Validator {
+	return func(input string) (string, error) {
+		input = strings.TrimSpace(input)
+		for _, o := range options {
+			if strings.EqualFold(o, input) {
+				return o, nil
+			}
+		}
+		return "", fmt.Errorf("Please choose one of: %s.", strings.Join(options, ", "))
+	}
+}
+
+// Step represents single question of a Dialog.
+type Step struct {
+	Name     string
+	Prompt   Prompt
+	Validate Validator
+	// Timeout overrides Dialog's timeout for the step.
+	Timeout time.Duration
+	// Next, if set, returns name of the step following this one, based on data collected so far.
+	// Empty name ends the dialog.
+	Next func(data map[string]string) string
+}
+
+// WithTimeout sets time the user has to answer the step.
+func (s *Step) WithTimeout(timeout time.Duration) *Step {
+	s.Timeout = timeout
+	return s
+}
+
+// WithNext sets function choosing step following this one.
+func (s *Step) WithNext(next func(data map[string]string) string) *Step {
+	s.Next = next
+	return s
+}
+
+// CompleteHandler is called with collected data when the dialog is completed.
+type CompleteHandler func(ctx *bot.Context, data map[string]string) error
+
+// TimeoutHandler is called when the user answers after step's timeout.
+type TimeoutHandler func(ctx *bot.Context, data map[string]string) error
+
+// Dialog is a declarative list of steps.
+type Dialog struct {
+	Name    string
+	Timeout time.Duration
+
+	steps      []*Step
+	byName     map[string]int
+	onComplete CompleteHandler
+	onTimeout  TimeoutHandler
+}
+
+// New creates empty Dialog with given name. Steps' answers never expire by default.
+func New(name string) *Dialog {
+	return &Dialog{
+		Name:   name,
+		byName: make(map[string]int),
+	}
+}
+
+// Ask appends step with given prompt and validator to the Dialog. If validator is nil, Any is used.
+func (d *Dialog) Ask(name string, prompt Prompt, validate Validator) *Step {
+	if validate == nil {
+		validate = Any
+	}
+	s := &Step{Name: name, Prompt: prompt, Validate: validate}
+	d.byName[name] = len(d.steps)
+	d.steps = append(d.steps, s)
+	return s
+}
+
+// WithTimeout sets default time the user has to answer each step.
+func (d *Dialog) WithTimeout(timeout time.Duration) *Dialog {
+	d.Timeout = timeout
+	return d
+}
+
+// OnComplete sets handler called when all steps are answered.
+func (d *Dialog) OnComplete(h CompleteHandler) *Dialog {
+	d.onComplete = h
+	return d
+}
+
+// OnTimeout sets handler called when the user answers too late. The dialog is cancelled afterwards.
+func (d *Dialog) OnTimeout(h TimeoutHandler) *Dialog {
+	d.onTimeout = h
+	return d
+}
+
+func (d *Dialog) step(name string) *Step {
+	i, exists := d.byName[name]
+	if !exists {
+		return nil
+	}
+	return d.steps[i]
+}
+
+// next returns name of the step following given one or an empty string if the dialog is over.
+func (d *Dialog) next(s *Step, data map[string]string) string {
+	if s.Next != nil {
+		return s.Next(data)
+	}
+	i := d.byName[s.Name] + 1
+	if i >= len(d.steps) {
+		return ""
+	}
+	return d.steps[i].Name
+}
+
+func (d *Dialog) timeout(s *Step) time.Duration {
+	if s.Timeout > 0 {
+		return s.Timeout
+	}
+	return d.Timeout
+}
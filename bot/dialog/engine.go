@@ -0,0 +1,169 @@
+package dialog
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/livechat/lc-sdk-go/v2/bot"
+	"github.com/livechat/lc-sdk-go/v2/objects"
+)
+
+// Engine runs dialogs and keeps their state in Storage.
+type Engine struct {
+	storage  Storage
+	byThread bool
+	now      func() time.Time
+
+	mu      sync.RWMutex
+	dialogs map[string]*Dialog
+}
+
+// NewEngine creates Engine storing dialogs' state in given Storage. Dialogs are keyed by chat ID.
+func NewEngine(storage Storage) *Engine {
+	return &Engine{
+		storage: storage,
+		now:     time.Now,
+		dialogs: make(map[string]*Dialog),
+	}
+}
+
+// KeyByThread makes Engine key dialogs by thread ID, so that dialog doesn't survive end of a thread.
+func (e *Engine) KeyByThread() *Engine {
+	e.byThread = true
+	return e
+}
+
+// WithClock replaces function used by Engine to get current time.
+func (e *Engine) WithClock(now func() time.Time) *Engine {
+	e.now = now
+	return e
+}
+
+// Register adds Dialog to the Engine, replacing dialog with the same name if any.
+func (e *Engine) Register(d *Dialog) *Engine {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.dialogs[d.Name] = d
+	return e
+}
+
+func (e *Engine) dialog(name string) (*Dialog, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	d, exists := e.dialogs[name]
+	if !exists {
+		return nil, fmt.Errorf("dialog %q not registered", name)
+	}
+	if len(d.steps) == 0 {
+		return nil, fmt.Errorf("dialog %q has no steps", name)
+	}
+	return d, nil
+}
+
+func (e *Engine) key(ctx *bot.Context) Key {
+	k := Key{ChatID: ctx.ChatID}
+	if e.byThread {
+		k.ThreadID = ctx.ThreadID
+	}
+	return k
+}
+
+// Start starts dialog with given name in context's chat, replacing dialog in progress if any.
+func (e *Engine) Start(ctx *bot.Context, name string) error {
+	d, err := e.dialog(name)
+	if err != nil {
+		return err
+	}
+	return e.ask(ctx, &State{
+		Dialog: d.Name,
+		Data:   make(map[string]string),
+	}, d.steps[0])
+}
+
+// Cancel ends dialog in progress in context's chat, if any.
+func (e *Engine) Cancel(ctx *bot.Context) error {
+	return e.storage.Delete(e.key(ctx))
+}
+
+// Active returns state of dialog in progress in context's chat or nil if there is none.
+func (e *Engine) Active(ctx *bot.Context) (*State, error) {
+	return e.storage.Load(e.key(ctx))
+}
+
+// Handle passes user's answer to dialog in progress in context's chat.
+// It returns false if there is no dialog in progress.
+func (e *Engine) Handle(ctx *bot.Context, input string) (bool, error) {
+	key := e.key(ctx)
+	state, err := e.storage.Load(key)
+	if err != nil || state == nil {
+		return false, err
+	}
+	d, err := e.dialog(state.Dialog)
+	if err != nil {
+		return true, err
+	}
+	step := d.step(state.Step)
+	if step == nil {
+		return true, fmt.Errorf("dialog %q has no step %q", d.Name, state.Step)
+	}
+
+	if timeout := d.timeout(step); timeout > 0 && e.now().Sub(state.UpdatedAt) > timeout {
+		if err := e.storage.Delete(key); err != nil {
+			return true, err
+		}
+		if d.onTimeout != nil {
+			return true, d.onTimeout(ctx, state.Data)
+		}
+		return true, nil
+	}
+
+	value, vErr := step.Validate(input)
+	if vErr != nil {
+		if _, err := ctx.Send(objects.NewMessage(vErr.Error())); err != nil {
+			return true, err
+		}
+		return true, e.ask(ctx, state, step)
+	}
+	state.Data[step.Name] = value
+
+	nextName := d.next(step, state.Data)
+	if nextName == "" {
+		if err := e.storage.Delete(key); err != nil {
+			return true, err
+		}
+		if d.onComplete != nil {
+			return true, d.onComplete(ctx, state.Data)
+		}
+		return true, nil
+	}
+	next := d.step(nextName)
+	if next == nil {
+		return true, fmt.Errorf("dialog %q has no step %q", d.Name, nextName)
+	}
+	return true, e.ask(ctx, state, next)
+}
+
+func (e *Engine) ask(ctx *bot.Context, state *State, s *Step) error {
+	state.Step = s.Name
+	state.UpdatedAt = e.now()
+	if err := e.storage.Save(e.key(ctx), state); err != nil {
+		return err
+	}
+	_, err := ctx.Send(s.Prompt.Event())
+	return err
+}
+
+// MessageHandler returns bot.MessageHandler passing messages to dialogs in progress.
+// Messages sent outside of dialogs are passed to fallback handler, if not nil.
+func (e *Engine) MessageHandler(fallback bot.MessageHandler) bot.MessageHandler {
+	return func(ctx *bot.Context, m *objects.Message) error {
+		handled, err := e.Handle(ctx, m.Text)
+		if handled || fallback == nil {
+			return err
+		}
+		return fallback(ctx, m)
+	}
+}
@@ -0,0 +1,160 @@
+package dialog_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/livechat/lc-sdk-go/v2/bot"
+	"github.com/livechat/lc-sdk-go/v2/bot/dialog"
+	"github.com/livechat/lc-sdk-go/v2/objects"
+	"github.com/livechat/lc-sdk-go/v2/webhooks"
+	"github.com/livechat/lc-sdk-go/v2/webhooks/webhooktest"
+)
+
+type fakeAgentAPI struct {
+	sent []interface{}
+}
+
+func (f *fakeAgentAPI) SetAuthorID(authorID string) {}
+
+func (f *fakeAgentAPI) SendEvent(chatID string, event interface{}, attachToLastThread bool) (string, error) {
+	f.sent = append(f.sent, event)
+	return "event_id", nil
+}
+
+func (f *fakeAgentAPI) SetRoutingStatus(agentID string, status objects.RoutingStatus) error {
+	return nil
+}
+
+func (f *fakeAgentAPI) DeactivateChat(chatID string) error { return nil }
+
+func (f *fakeAgentAPI) TransferChat(chatID, targetType string, ids []interface{}, force bool) error {
+	return nil
+}
+
+func (f *fakeAgentAPI) lastText() string {
+	switch e := f.sent[len(f.sent)-1].(type) {
+	case *objects.Message:
+		return e.Text
+	case *objects.RichMessage:
+		return e.Elements[0].Title
+	}
+	return ""
+}
+
+type testBot struct {
+	t         *testing.T
+	api       *fakeAgentAPI
+	sim       *webhooktest.Simulator
+	now       time.Time
+	completed map[string]string
+	timedOut  bool
+}
+
+func newTestBot(t *testing.T) *testBot {
+	tb := &testBot{t: t, api: &fakeAgentAPI{}, now: time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)}
+
+	d := dialog.New("handoff").WithTimeout(time.Minute).
+		OnComplete(func(ctx *bot.Context, data map[string]string) error {
+			tb.completed = data
+			return nil
+		}).
+		OnTimeout(func(ctx *bot.Context, data map[string]string) error {
+			tb.timedOut = true
+			return nil
+		})
+	d.Ask("email", dialog.Text("What's your email?"), dialog.Email)
+	d.Ask("confirm", dialog.QuickReplies("Shall I transfer you?", "yes", "no"), dialog.OneOf("yes", "no")).
+		WithNext(func(data map[string]string) string {
+			if data["confirm"] == "no" {
+				return "reason"
+			}
+			return ""
+		})
+	d.Ask("reason", dialog.Text("Why not?"), nil)
+
+	engine := dialog.NewEngine(dialog.NewMemoryStorage()).Register(d).WithClock(func() time.Time { return tb.now })
+	b := bot.New("Bot", func(licenseID int) (bot.AgentAPI, error) { return tb.api, nil }).
+		WithID("bot_id").
+		OnMessage(engine.MessageHandler(func(ctx *bot.Context, m *objects.Message) error {
+			return engine.Start(ctx, "handoff")
+		}))
+	tb.sim = webhooktest.NewSimulator(b.Handler(), 100, "")
+	return tb
+}
+
+func (tb *testBot) say(text string) {
+	tb.t.Helper()
+	event, err := webhooktest.EventFrom(objects.NewMessage(text))
+	if err != nil {
+		tb.t.Fatalf("couldn't create event: %v", err)
+	}
+	event.AuthorID = "customer_id"
+	payload := webhooktest.SamplePayload("incoming_event").(*webhooks.IncomingEvent)
+	payload.Event = event
+	tb.sim.ExpectOK(tb.t, "incoming_event", payload)
+}
+
+func (tb *testBot) expectReply(text string) {
+	tb.t.Helper()
+	if got := tb.api.lastText(); got != text {
+		tb.t.Errorf("invalid reply: %q, expected: %q", got, text)
+	}
+}
+
+func TestDialogShouldCollectAnswers(t *testing.T) {
+	tb := newTestBot(t)
+
+	tb.say("hi")
+	tb.expectReply("What's your email?")
+	tb.say("John <john@example.com>")
+	tb.expectReply("Shall I transfer you?")
+	if _, ok := tb.api.sent[len(tb.api.sent)-1].(*objects.RichMessage); !ok {
+		t.Errorf("quick replies prompt should be sent as rich message")
+	}
+	tb.say("YES")
+
+	if tb.completed["email"] != "john@example.com" || tb.completed["confirm"] != "yes" {
+		t.Errorf("invalid collected data: %v", tb.completed)
+	}
+
+	tb.say("hi again")
+	tb.expectReply("What's your email?")
+}
+
+func TestDialogShouldRepeatPromptOnInvalidAnswer(t *testing.T) {
+	tb := newTestBot(t)
+
+	tb.say("hi")
+	tb.say("not an email")
+	if len(tb.api.sent) != 3 || tb.api.sent[1].(*objects.Message).Text != "Please provide a valid email address." {
+		t.Errorf("invalid sent events: %v", tb.api.sent)
+	}
+	tb.expectReply("What's your email?")
+}
+
+func TestDialogShouldFollowCustomNextStep(t *testing.T) {
+	tb := newTestBot(t)
+
+	tb.say("hi")
+	tb.say("john@example.com")
+	tb.say("no")
+	tb.expectReply("Why not?")
+	tb.say("I'm fine")
+
+	if tb.completed["reason"] != "I'm fine" {
+		t.Errorf("invalid collected data: %v", tb.completed)
+	}
+}
+
+func TestDialogShouldTimeOut(t *testing.T) {
+	tb := newTestBot(t)
+
+	tb.say("hi")
+	tb.now = tb.now.Add(2 * time.Minute)
+	tb.say("john@example.com")
+
+	if !tb.timedOut || tb.completed != nil {
+		t.Errorf("dialog should time out")
+	}
+}
@@ -0,0 +1,241 @@
+package dialog
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/livechat/lc-sdk-go/v2/objects"
+	"github.com/livechat/lc-sdk-go/v2/properties"
+)
+
+// Key identifies dialog in progress. ThreadID is empty for dialogs keyed by chat.
+type Key struct {
+	ChatID   string
+	ThreadID string
+}
+
+func (k Key) String() string {
+	if k.ThreadID == "" {
+		return k.ChatID
+	}
+	return k.ChatID + "/" + k.ThreadID
+}
+
+// State represents progress of a dialog.
+type State struct {
+	Dialog    string            `json:"dialog"`
+	Step      string            `json:"step"`
+	Data      map[string]string `json:"data"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+// Storage keeps state of dialogs in progress.
+type Storage interface {
+	// Load returns state of dialog with given key or nil if there is none.
+	Load(key Key) (*State, error)
+	Save(key Key, state *State) error
+	Delete(key Key) error
+}
+
+// MemoryStorage keeps dialogs' state in memory.
+type MemoryStorage struct {
+	mu     sync.Mutex
+	states map[Key]State
+}
+
+// NewMemoryStorage creates empty MemoryStorage.
+func NewMemoryStorage() *MemoryStorage {
+	return &MemoryStorage{states: make(map[Key]State)}
+}
+
+// Load implements Storage.
+func (s *MemoryStorage) Load(key Key) (*State, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, exists := s.states[key]
+	if !exists {
+		return nil, nil
+	}
+	state.Data = copyData(state.Data)
+	return &state, nil
+}
+
+// Save implements Storage.
+func (s *MemoryStorage) Save(key Key, state *State) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored := *state
+	stored.Data = copyData(state.Data)
+	s.states[key] = stored
+	return nil
+}
+
+// Delete implements Storage.
+func (s *MemoryStorage) Delete(key Key) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.states, key)
+	return nil
+}
+
+func copyData(data map[string]string) map[string]string {
+	copied := make(map[string]string, len(data))
+	for k, v := range data {
+		copied[k] = v
+	}
+	return copied
+}
+
+// FileStorage keeps dialogs' state in JSON files, one per dialog.
+type FileStorage struct {
+	dir string
+}
+
+// NewFileStorage creates FileStorage keeping files in given directory, which is created if needed.
+func NewFileStorage(dir string) (*FileStorage, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("couldn't create storage directory: %v", err)
+	}
+	return &FileStorage{dir: dir}, nil
+}
+
+func (s *FileStorage) path(key Key) string {
+	return filepath.Join(s.dir, url.PathEscape(key.String())+".json")
+}
+
+// Load implements Storage.
+func (s *FileStorage) Load(key Key) (*State, error) {
+	data, err := ioutil.ReadFile(s.path(key))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("couldn't decode dialog state: %v", err)
+	}
+	return &state, nil
+}
+
+// Save implements Storage. State is written to a temporary file first, so that it is never partially written.
+func (s *FileStorage) Save(key Key, state *State) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(s.dir, "tmp-")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path(key))
+}
+
+// Delete implements Storage.
+func (s *FileStorage) Delete(key Key) error {
+	err := os.Remove(s.path(key))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// PropertiesAPI is a subset of agent.API used by PropertiesStorage.
+type PropertiesAPI interface {
+	GetChat(chatID string, threadID string) (objects.Chat, error)
+	UpdateChatProperties(chatID string, properties objects.Properties) error
+	DeleteChatProperties(chatID string, properties map[string][]string) error
+	UpdateThreadProperties(chatID, threadID string, properties objects.Properties) error
+	DeleteThreadProperties(chatID, threadID string, properties map[string][]string) error
+}
+
+// StateProperty is name of property keeping dialog state.
+const StateProperty = "dialog_state"
+
+// PropertiesStorage keeps dialogs' state as JSON encoded string property of chat or thread (for keys with ThreadID).
+type PropertiesStorage struct {
+	api    PropertiesAPI
+	schema *properties.Schema
+}
+
+// NewPropertiesStorage creates PropertiesStorage keeping state in given namespace, ie. application's client ID.
+//
+// StateProperty has to be registered beforehand, ie. with configuration returned by Schema.
+func NewPropertiesStorage(api PropertiesAPI, namespace string) *PropertiesStorage {
+	schema := properties.NewSchema(namespace)
+	schema.DefineString(StateProperty, "").
+		WithDescription("State of bot dialog in progress").
+		WithAccess("chat", "agent", true, true).
+		WithAccess("thread", "agent", true, true)
+	return &PropertiesStorage{api: api, schema: schema}
+}
+
+// Schema returns schema of properties used by PropertiesStorage.
+func (s *PropertiesStorage) Schema() *properties.Schema {
+	return s.schema
+}
+
+// Load implements Storage.
+func (s *PropertiesStorage) Load(key Key) (*State, error) {
+	chat, err := s.api.GetChat(key.ChatID, key.ThreadID)
+	if err != nil {
+		return nil, err
+	}
+	props := chat.Properties
+	if key.ThreadID != "" {
+		props = chat.Thread.Properties
+	}
+	raw, err := s.schema.String(props, StateProperty)
+	if err != nil || raw == "" {
+		return nil, err
+	}
+	var state State
+	if err := json.Unmarshal([]byte(raw), &state); err != nil {
+		return nil, fmt.Errorf("couldn't decode dialog state: %v", err)
+	}
+	return &state, nil
+}
+
+// Save implements Storage.
+func (s *PropertiesStorage) Save(key Key, state *State) error {
+	raw, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	props := objects.Properties{}
+	if err := s.schema.Set(props, StateProperty, string(raw)); err != nil {
+		return err
+	}
+	if key.ThreadID != "" {
+		return s.api.UpdateThreadProperties(key.ChatID, key.ThreadID, props)
+	}
+	return s.api.UpdateChatProperties(key.ChatID, props)
+}
+
+// Delete implements Storage.
+func (s *PropertiesStorage) Delete(key Key) error {
+	names := s.schema.Names(StateProperty)
+	if key.ThreadID != "" {
+		return s.api.DeleteThreadProperties(key.ChatID, key.ThreadID, names)
+	}
+	return s.api.DeleteChatProperties(key.ChatID, names)
+}
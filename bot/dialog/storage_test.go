@@ -0,0 +1,109 @@
+package dialog_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/livechat/lc-sdk-go/v2/agent"
+	"github.com/livechat/lc-sdk-go/v2/bot/dialog"
+	"github.com/livechat/lc-sdk-go/v2/objects"
+)
+
+var _ dialog.PropertiesAPI = &agent.API{}
+
+type fakePropertiesAPI struct {
+	chat objects.Chat
+}
+
+func (f *fakePropertiesAPI) GetChat(chatID string, threadID string) (objects.Chat, error) {
+	return f.chat, nil
+}
+
+func (f *fakePropertiesAPI) UpdateChatProperties(chatID string, properties objects.Properties) error {
+	f.chat.Properties = properties
+	return nil
+}
+
+func (f *fakePropertiesAPI) DeleteChatProperties(chatID string, properties map[string][]string) error {
+	f.chat.Properties = nil
+	return nil
+}
+
+func (f *fakePropertiesAPI) UpdateThreadProperties(chatID, threadID string, properties objects.Properties) error {
+	f.chat.Thread.Properties = properties
+	return nil
+}
+
+func (f *fakePropertiesAPI) DeleteThreadProperties(chatID, threadID string, properties map[string][]string) error {
+	f.chat.Thread.Properties = nil
+	return nil
+}
+
+func testStorage(t *testing.T, s dialog.Storage, key dialog.Key) {
+	t.Helper()
+
+	if state, err := s.Load(key); state != nil || err != nil {
+		t.Errorf("empty storage should return no state: %v, %v", state, err)
+	}
+
+	saved := &dialog.State{
+		Dialog:    "handoff",
+		Step:      "email",
+		Data:      map[string]string{"name": "John"},
+		UpdatedAt: time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC),
+	}
+	if err := s.Save(key, saved); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	saved.Data["name"] = "modified"
+
+	state, err := s.Load(key)
+	if err != nil || state == nil {
+		t.Fatalf("Load failed: %v, %v", state, err)
+	}
+	if state.Dialog != "handoff" || state.Step != "email" || state.Data["name"] != "John" || !state.UpdatedAt.Equal(saved.UpdatedAt) {
+		t.Errorf("invalid loaded state: %+v", state)
+	}
+
+	if err := s.Delete(key); err != nil {
+		t.Errorf("Delete failed: %v", err)
+	}
+	if state, err := s.Load(key); state != nil || err != nil {
+		t.Errorf("deleted state should not be returned: %v, %v", state, err)
+	}
+	if err := s.Delete(key); err != nil {
+		t.Errorf("deleting missing state should not fail: %v", err)
+	}
+}
+
+func TestMemoryStorage(t *testing.T) {
+	testStorage(t, dialog.NewMemoryStorage(), dialog.Key{ChatID: "chat"})
+}
+
+func TestFileStorage(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dialog")
+	if err != nil {
+		t.Fatalf("couldn't create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	s, err := dialog.NewFileStorage(dir)
+	if err != nil {
+		t.Fatalf("NewFileStorage failed: %v", err)
+	}
+	testStorage(t, s, dialog.Key{ChatID: "chat", ThreadID: "../thread"})
+}
+
+func TestPropertiesStorage(t *testing.T) {
+	api := &fakePropertiesAPI{}
+	s := dialog.NewPropertiesStorage(api, "client_id")
+
+	testStorage(t, s, dialog.Key{ChatID: "chat"})
+	testStorage(t, s, dialog.Key{ChatID: "chat", ThreadID: "thread"})
+
+	if c := s.Schema().PropertyConfigs()[dialog.StateProperty]; c == nil || c.Type != "string" {
+		t.Errorf("invalid state property config: %v", c)
+	}
+}
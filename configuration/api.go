@@ -210,8 +210,8 @@ func (a *API) RegisterProperties(properties map[string]*PropertyConfig) error {
 	return a.Call("register_properties", properties, &emptyResponse{})
 }
 
-// ListRegisteredProperties return list of properties along with their configuration
-func (a *API) ListRegisteredProperties(getAll bool) (map[string]*PropertyConfig, error) {
+// ListRegisteredProperties return list of properties along with their configuration, grouped by namespace
+func (a *API) ListRegisteredProperties(getAll bool) (map[string]map[string]*PropertyConfig, error) {
 	var resp listRegisteredPropertiesResponse
 	err := a.Call("list_registered_properties", &listRegisteredPropertiesRequest{
 		All: getAll,
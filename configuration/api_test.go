@@ -509,7 +509,7 @@ func TestListRegisteredPropertiesShouldReturnDataReceivedFromConfApi(t *testing.
 		t.Errorf("ListRegisteredProperties failed: %v", rErr)
 	}
 
-	if _, exists := resp["58737b5829e65621a45d598aa6f2ed8e"]; !exists || len(resp) != 1 {
+	if _, exists := resp["58737b5829e65621a45d598aa6f2ed8e"]["greeting"]; !exists || len(resp) != 1 {
 		t.Errorf("Invalid property configs: %v", resp)
 	}
 }
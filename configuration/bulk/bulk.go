@@ -0,0 +1,98 @@
+package bulk
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/livechat/lc-sdk-go/v2/configuration"
+)
+
+// API is a subset of configuration.API used by Importer and Offboarder.
+type API interface {
+	ListGroups(fields []string) ([]*configuration.Group, error)
+	UpdateGroup(id int32, name, language string, agentPriorities map[string]configuration.GroupPriority) error
+	ListAgents(groupIDs []int32, fields []string) ([]*configuration.Agent, error)
+	CreateAgent(id string, fields *configuration.AgentFields) (string, error)
+	UpdateAgent(id string, fields *configuration.AgentFields) error
+	SuspendAgent(id string) error
+	DeleteAgent(id string) error
+}
+
+// DefaultConcurrency is a default number of agents processed at the same time.
+const DefaultConcurrency = 4
+
+// Operation describes what was done with an agent.
+type Operation string
+
+// Possible values of Operation.
+const (
+	Create   Operation = "create"
+	Update   Operation = "update"
+	Offboard Operation = "offboard"
+)
+
+// Result describes outcome of processing single agent.
+type Result struct {
+	// Index is a position of the agent in the input, starting from 1.
+	Index     int
+	AgentID   string
+	Operation Operation
+	// Err is nil if the agent was processed successfully.
+	Err error
+}
+
+func (r Result) String() string {
+	if r.Err != nil {
+		return fmt.Sprintf("#%d %s: %s failed: %v", r.Index, r.AgentID, r.Operation, r.Err)
+	}
+	return fmt.Sprintf("#%d %s: %s", r.Index, r.AgentID, r.Operation)
+}
+
+// Results lists Result of every processed agent in the order of input.
+type Results []Result
+
+// Failed returns Results with errors.
+func (rs Results) Failed() Results {
+	var failed Results
+	for _, r := range rs {
+		if r.Err != nil {
+			failed = append(failed, r)
+		}
+	}
+	return failed
+}
+
+// Err returns error summarizing failures, or nil if all agents were processed successfully.
+func (rs Results) Err() error {
+	failed := rs.Failed()
+	if len(failed) == 0 {
+		return nil
+	}
+	msgs := make([]string, len(failed))
+	for i, r := range failed {
+		msgs[i] = r.String()
+	}
+	return fmt.Errorf("%d of %d agents failed: %s", len(failed), len(rs), strings.Join(msgs, "; "))
+}
+
+// parallel calls fn for every index in [0, n) running at most concurrency calls at the same time.
+func parallel(n, concurrency int, fn func(i int)) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			fn(i)
+		}(i)
+	}
+	wg.Wait()
+}
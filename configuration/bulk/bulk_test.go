@@ -0,0 +1,220 @@
+package bulk_test
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/livechat/lc-sdk-go/v2/configuration"
+	"github.com/livechat/lc-sdk-go/v2/configuration/bulk"
+)
+
+var _ bulk.API = &configuration.API{}
+
+type fakeAPI struct {
+	mu      sync.Mutex
+	groups  []*configuration.Group
+	agents  map[string]*configuration.AgentFields
+	calls   []string
+	failing map[string]bool
+
+	running, maxRunning int
+}
+
+func newFakeAPI() *fakeAPI {
+	return &fakeAPI{
+		groups: []*configuration.Group{
+			{ID: 0, Name: "General", AgentPriorities: map[string]configuration.GroupPriority{"old@example.com": configuration.Normal, "keep@example.com": configuration.Normal}},
+			{ID: 1, Name: "Sales", AgentPriorities: map[string]configuration.GroupPriority{"old@example.com": configuration.First}},
+		},
+		agents: map[string]*configuration.AgentFields{
+			"old@example.com":  {Name: "Old"},
+			"keep@example.com": {Name: "Keep"},
+			"temp@example.com": {Name: "Temp"},
+		},
+		failing: make(map[string]bool),
+	}
+}
+
+func (f *fakeAPI) call(name, id string) error {
+	f.mu.Lock()
+	f.calls = append(f.calls, name+" "+id)
+	f.running++
+	if f.running > f.maxRunning {
+		f.maxRunning = f.running
+	}
+	failing := f.failing[name+" "+id]
+	f.mu.Unlock()
+
+	time.Sleep(time.Millisecond)
+
+	f.mu.Lock()
+	f.running--
+	f.mu.Unlock()
+	if failing {
+		return errors.New("service unavailable")
+	}
+	return nil
+}
+
+func (f *fakeAPI) ListGroups(fields []string) ([]*configuration.Group, error) {
+	return f.groups, nil
+}
+
+func (f *fakeAPI) UpdateGroup(id int32, name, language string, agentPriorities map[string]configuration.GroupPriority) error {
+	if err := f.call("update_group", name); err != nil {
+		return err
+	}
+	f.groups[id].AgentPriorities = agentPriorities
+	return nil
+}
+
+func (f *fakeAPI) ListAgents(groupIDs []int32, fields []string) ([]*configuration.Agent, error) {
+	var agents []*configuration.Agent
+	for id, fields := range f.agents {
+		agents = append(agents, &configuration.Agent{ID: id, AgentFields: fields})
+	}
+	return agents, nil
+}
+
+func (f *fakeAPI) CreateAgent(id string, fields *configuration.AgentFields) (string, error) {
+	if err := f.call("create_agent", id); err != nil {
+		return "", err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.agents[id] = fields
+	return id, nil
+}
+
+func (f *fakeAPI) UpdateAgent(id string, fields *configuration.AgentFields) error {
+	if err := f.call("update_agent", id); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.agents[id] = fields
+	return nil
+}
+
+func (f *fakeAPI) SuspendAgent(id string) error {
+	return f.call("suspend_agent", id)
+}
+
+func (f *fakeAPI) DeleteAgent(id string) error {
+	if err := f.call("delete_agent", id); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.agents, id)
+	return nil
+}
+
+func TestImport(t *testing.T) {
+	api := newFakeAPI()
+	rows, err := bulk.ReadCSV(strings.NewReader(`email,name,groups
+new@example.com,New,Sales:first
+old@example.com,Old Renamed,
+invalid,Invalid,
+NEW@example.com,Duplicate,
+other@example.com,Other,Marketing
+`))
+	if err != nil {
+		t.Fatalf("ReadCSV failed: %v", err)
+	}
+
+	results, err := bulk.NewImporter(api).Import(rows)
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+
+	if len(results) != 5 || results[0].Operation != bulk.Create || results[1].Operation != bulk.Update {
+		t.Errorf("invalid results: %v", results)
+	}
+	if failed := results.Failed(); len(failed) != 3 || failed[0].Index != 3 || failed[1].Index != 4 || failed[2].Index != 5 {
+		t.Errorf("invalid failed results: %v", failed)
+	}
+	if err := results.Err(); err == nil || !strings.HasPrefix(err.Error(), "3 of 5 agents failed") {
+		t.Errorf("invalid error: %v", err)
+	}
+
+	created := api.agents["new@example.com"]
+	if created == nil || len(created.Groups) != 1 || created.Groups[0] != (configuration.GroupConfig{ID: 1, Priority: configuration.First}) {
+		t.Errorf("invalid created agent: %+v", created)
+	}
+	if api.agents["old@example.com"].Name != "Old Renamed" {
+		t.Errorf("existing agent should be updated")
+	}
+	if len(api.calls) != 2 {
+		t.Errorf("invalid rows should not be sent: %v", api.calls)
+	}
+}
+
+func TestImportShouldLimitConcurrency(t *testing.T) {
+	api := newFakeAPI()
+	var rows []*bulk.Row
+	for _, name := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+		rows = append(rows, &bulk.Row{Email: name + "@example.com", Name: name})
+	}
+
+	results, err := bulk.NewImporter(api).WithConcurrency(3).Import(rows)
+	if err != nil || results.Err() != nil {
+		t.Fatalf("Import failed: %v, %v", err, results.Err())
+	}
+	if api.maxRunning > 3 || len(api.calls) != len(rows) {
+		t.Errorf("invalid concurrency: %d, calls: %d", api.maxRunning, len(api.calls))
+	}
+}
+
+func TestOffboard(t *testing.T) {
+	api := newFakeAPI()
+	api.failing["suspend_agent temp@example.com"] = true
+
+	results, err := bulk.NewOffboarder(api).Offboard("old@example.com", "temp@example.com")
+	if err != nil {
+		t.Fatalf("Offboard failed: %v", err)
+	}
+
+	if results[0].Err != nil || results[1].Err == nil {
+		t.Errorf("invalid results: %v", results)
+	}
+	if _, exists := api.agents["old@example.com"]; exists {
+		t.Errorf("offboarded agent should be deleted")
+	}
+	if _, exists := api.agents["temp@example.com"]; !exists {
+		t.Errorf("agent which couldn't be suspended should not be deleted")
+	}
+	if p := api.groups[0].AgentPriorities; len(p) != 1 || p["old@example.com"] != "" {
+		t.Errorf("offboarded agent should be removed from group: %v", p)
+	}
+	if p := api.groups[1].AgentPriorities; len(p) != 1 {
+		t.Errorf("group should not be left without agents: %v", p)
+	}
+
+	sort.Strings(api.calls)
+	expected := "delete_agent old@example.com, suspend_agent old@example.com, suspend_agent temp@example.com, update_group General"
+	if calls := strings.Join(api.calls, ", "); calls != expected {
+		t.Errorf("invalid calls: %v", calls)
+	}
+}
+
+func TestOffboardShouldNotDeleteAgentsRemainingInGroups(t *testing.T) {
+	api := newFakeAPI()
+	api.failing["update_group General"] = true
+
+	results, err := bulk.NewOffboarder(api).Offboard("old@example.com")
+	if err != nil {
+		t.Fatalf("Offboard failed: %v", err)
+	}
+
+	if results.Err() == nil {
+		t.Errorf("Offboard should fail")
+	}
+	if _, exists := api.agents["old@example.com"]; !exists {
+		t.Errorf("agent should not be deleted")
+	}
+}
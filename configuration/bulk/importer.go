@@ -0,0 +1,112 @@
+package bulk
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/livechat/lc-sdk-go/v2/configuration"
+)
+
+// Importer provisions agents described with Rows.
+type Importer struct {
+	api         API
+	concurrency int
+}
+
+// NewImporter creates Importer processing DefaultConcurrency agents at the same time.
+func NewImporter(api API) *Importer {
+	return &Importer{
+		api:         api,
+		concurrency: DefaultConcurrency,
+	}
+}
+
+// WithConcurrency sets maximum number of agents processed at the same time.
+func (i *Importer) WithConcurrency(n int) *Importer {
+	i.concurrency = n
+	return i
+}
+
+// Import creates agents described with rows, or updates them if they already exist.
+//
+// Invalid rows, rows duplicating email of a previous row and rows referencing unknown groups are reported
+// in Results without calling the API. Returned error is not nil only if groups or agents couldn't be listed.
+func (i *Importer) Import(rows []*Row) (Results, error) {
+	results := make(Results, len(rows))
+	seen := make(map[string]bool, len(rows))
+	var valid []int
+	for n, row := range rows {
+		results[n] = Result{Index: row.Index, AgentID: row.Email, Operation: Create}
+		email := strings.ToLower(row.Email)
+		switch err := row.Validate(); {
+		case err != nil:
+			results[n].Err = err
+		case seen[email]:
+			results[n].Err = fmt.Errorf("duplicated email: %q", row.Email)
+		default:
+			seen[email] = true
+			valid = append(valid, n)
+		}
+	}
+	if len(valid) == 0 {
+		return results, nil
+	}
+
+	groups, err := i.api.ListGroups(nil)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't list groups: %v", err)
+	}
+	groupIDs := make(map[string]uint, len(groups))
+	for _, g := range groups {
+		if _, exists := groupIDs[g.Name]; !exists {
+			groupIDs[g.Name] = uint(g.ID)
+		}
+	}
+
+	agents, err := i.api.ListAgents(nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't list agents: %v", err)
+	}
+	existing := make(map[string]bool, len(agents))
+	for _, a := range agents {
+		existing[strings.ToLower(a.ID)] = true
+	}
+
+	parallel(len(valid), i.concurrency, func(v int) {
+		n := valid[v]
+		row := rows[n]
+		fields, err := row.fields(groupIDs)
+		if err != nil {
+			results[n].Err = err
+			return
+		}
+		if existing[strings.ToLower(row.Email)] {
+			results[n].Operation = Update
+			results[n].Err = i.api.UpdateAgent(row.Email, fields)
+			return
+		}
+		_, results[n].Err = i.api.CreateAgent(row.Email, fields)
+	})
+	return results, nil
+}
+
+func (r *Row) fields(groupIDs map[string]uint) (*configuration.AgentFields, error) {
+	fields := &configuration.AgentFields{
+		Name:          r.Name,
+		Role:          r.Role,
+		MaxChatsCount: r.MaxChatsCount,
+		WorkScheduler: r.WorkScheduler,
+	}
+	for _, g := range r.Groups {
+		id, exists := groupIDs[g.Group]
+		if !exists {
+			return nil, fmt.Errorf("unknown group: %q", g.Group)
+		}
+		priority := g.Priority
+		if priority == "" {
+			priority = configuration.Normal
+		}
+		fields.Groups = append(fields.Groups, configuration.GroupConfig{ID: id, Priority: priority})
+	}
+	return fields, nil
+}
@@ -0,0 +1,91 @@
+package bulk
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/livechat/lc-sdk-go/v2/configuration"
+)
+
+// Offboarder removes agents from the license.
+type Offboarder struct {
+	api         API
+	concurrency int
+}
+
+// NewOffboarder creates Offboarder processing DefaultConcurrency agents at the same time.
+func NewOffboarder(api API) *Offboarder {
+	return &Offboarder{
+		api:         api,
+		concurrency: DefaultConcurrency,
+	}
+}
+
+// WithConcurrency sets maximum number of agents processed at the same time.
+func (o *Offboarder) WithConcurrency(n int) *Offboarder {
+	o.concurrency = n
+	return o
+}
+
+// Offboard removes given agents from their groups, so they stop receiving chats, suspends them to revoke
+// their access and deletes them.
+//
+// Agents which couldn't be removed from some group are suspended, but not deleted, so Offboard can be
+// retried. Groups which would be left without any agent keep offboarded agents until they are deleted,
+// as empty agent priorities cannot be sent to the API.
+//
+// Returned error is not nil only if groups couldn't be listed.
+func (o *Offboarder) Offboard(agentIDs ...string) (Results, error) {
+	results := make(Results, len(agentIDs))
+	offboarded := make(map[string]bool, len(agentIDs))
+	for n, id := range agentIDs {
+		results[n] = Result{Index: n + 1, AgentID: id, Operation: Offboard}
+		offboarded[id] = true
+	}
+	if len(agentIDs) == 0 {
+		return results, nil
+	}
+
+	groups, err := o.api.ListGroups([]string{"agent_priorities"})
+	if err != nil {
+		return nil, fmt.Errorf("couldn't list groups: %v", err)
+	}
+	groupErrors := make(map[string][]string)
+	for _, g := range groups {
+		priorities := make(map[string]configuration.GroupPriority, len(g.AgentPriorities))
+		var removed []string
+		for id, priority := range g.AgentPriorities {
+			if offboarded[id] {
+				removed = append(removed, id)
+				continue
+			}
+			priorities[id] = priority
+		}
+		if len(removed) == 0 || len(priorities) == 0 {
+			continue
+		}
+		if err := o.api.UpdateGroup(int32(g.ID), g.Name, g.LanguageCode, priorities); err != nil {
+			for _, id := range removed {
+				groupErrors[id] = append(groupErrors[id], fmt.Sprintf("group %d: %v", g.ID, err))
+			}
+		}
+	}
+
+	parallel(len(agentIDs), o.concurrency, func(n int) {
+		id := agentIDs[n]
+		if err := o.api.SuspendAgent(id); err != nil {
+			results[n].Err = fmt.Errorf("couldn't suspend agent: %v", err)
+			return
+		}
+		if errs := groupErrors[id]; len(errs) > 0 {
+			sort.Strings(errs)
+			results[n].Err = fmt.Errorf("couldn't remove agent from groups: %s", strings.Join(errs, "; "))
+			return
+		}
+		if err := o.api.DeleteAgent(id); err != nil {
+			results[n].Err = fmt.Errorf("couldn't delete agent: %v", err)
+		}
+	})
+	return results, nil
+}
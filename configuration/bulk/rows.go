@@ -0,0 +1,186 @@
+// Package bulk provisions and offboards many agents at once.
+//
+// Agents are described with Rows, which can be read from CSV or JSON. Importer validates the Rows,
+// resolves group names and creates (or updates already existing) agents concurrently, reporting
+// a Result for every Row. Offboarder removes agents from their groups, suspends them and finally
+// deletes them.
+package bulk
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/mail"
+	"strconv"
+	"strings"
+
+	"github.com/livechat/lc-sdk-go/v2/configuration"
+)
+
+// Roles which can be assigned to imported agents.
+var Roles = []string{"viceowner", "administrator", "normal"}
+
+// GroupAssignment describes membership of an agent in a group referenced by name.
+type GroupAssignment struct {
+	Group    string                      `json:"group"`
+	Priority configuration.GroupPriority `json:"priority,omitempty"`
+}
+
+// Row describes single agent to provision. Agents are identified by email.
+type Row struct {
+	// Index is a position of the Row in the input, starting from 1.
+	Index         int                         `json:"-"`
+	Email         string                      `json:"email"`
+	Name          string                      `json:"name"`
+	Role          string                      `json:"role,omitempty"`
+	Groups        []GroupAssignment           `json:"groups,omitempty"`
+	MaxChatsCount uint                        `json:"max_chats_count,omitempty"`
+	WorkScheduler configuration.WorkScheduler `json:"work_scheduler,omitempty"`
+
+	err error
+}
+
+// Validate checks if Row describes valid agent.
+func (r *Row) Validate() error {
+	if r.err != nil {
+		return r.err
+	}
+	if _, err := mail.ParseAddress(r.Email); err != nil || strings.ContainsAny(r.Email, "<> ") {
+		return fmt.Errorf("invalid email: %q", r.Email)
+	}
+	if strings.TrimSpace(r.Name) == "" {
+		return fmt.Errorf("missing name")
+	}
+	if r.Role != "" && !contains(Roles, r.Role) {
+		return fmt.Errorf("invalid role: %q", r.Role)
+	}
+	for _, g := range r.Groups {
+		if g.Group == "" {
+			return fmt.Errorf("missing group name")
+		}
+		switch g.Priority {
+		case "", configuration.First, configuration.Normal, configuration.Last, configuration.DoNotAssign:
+		default:
+			return fmt.Errorf("invalid priority of group %q: %q", g.Group, g.Priority)
+		}
+	}
+	if err := r.WorkScheduler.Validate(); err != nil {
+		return fmt.Errorf("invalid work scheduler: %v", err)
+	}
+	return nil
+}
+
+// ReadJSON reads Rows from JSON array.
+func ReadJSON(r io.Reader) ([]*Row, error) {
+	var rows []*Row
+	if err := json.NewDecoder(r).Decode(&rows); err != nil {
+		return nil, fmt.Errorf("couldn't decode rows: %v", err)
+	}
+	for i, row := range rows {
+		if row == nil {
+			return nil, fmt.Errorf("item %d: empty row", i+1)
+		}
+		row.Index = i + 1
+	}
+	return rows, nil
+}
+
+// CSV columns supported by ReadCSV.
+const (
+	ColumnEmail         = "email"
+	ColumnName          = "name"
+	ColumnRole          = "role"
+	ColumnGroups        = "groups"
+	ColumnMaxChatsCount = "max_chats_count"
+	ColumnWorkScheduler = "work_scheduler"
+)
+
+// ReadCSV reads Rows from CSV with a header. Column names are listed in Column constants,
+// only email and name columns are required.
+//
+// Groups are separated with semicolons and may contain priority after colon, ie. "Sales:first;Support".
+// Work scheduler is in format accepted by configuration.ParseWorkScheduler.
+//
+// Malformed values do not stop reading. They are reported by Validate of the Row instead.
+func ReadCSV(r io.Reader) ([]*Row, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("couldn't read header: %v", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{ColumnEmail, ColumnName} {
+		if _, exists := columns[required]; !exists {
+			return nil, fmt.Errorf("missing column: %q", required)
+		}
+	}
+
+	var rows []*Row
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, parseRecord(record, columns, len(rows)+1))
+	}
+}
+
+func parseRecord(record []string, columns map[string]int, index int) *Row {
+	value := func(column string) string {
+		if i, exists := columns[column]; exists {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	row := &Row{
+		Index: index,
+		Email: value(ColumnEmail),
+		Name:  value(ColumnName),
+		Role:  value(ColumnRole),
+	}
+	for _, g := range strings.Split(value(ColumnGroups), ";") {
+		if g = strings.TrimSpace(g); g == "" {
+			continue
+		}
+		parts := strings.SplitN(g, ":", 2)
+		assignment := GroupAssignment{Group: strings.TrimSpace(parts[0])}
+		if len(parts) == 2 {
+			assignment.Priority = configuration.GroupPriority(strings.TrimSpace(parts[1]))
+		}
+		row.Groups = append(row.Groups, assignment)
+	}
+	if v := value(ColumnMaxChatsCount); v != "" {
+		maxChats, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			row.err = fmt.Errorf("invalid max chats count: %q", v)
+		}
+		row.MaxChatsCount = uint(maxChats)
+	}
+	if v := value(ColumnWorkScheduler); v != "" && row.err == nil {
+		ws, err := configuration.ParseWorkScheduler(v)
+		if err != nil {
+			row.err = fmt.Errorf("invalid work scheduler: %v", err)
+		}
+		row.WorkScheduler = ws
+	}
+	return row
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,68 @@
+package bulk_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/livechat/lc-sdk-go/v2/configuration"
+	"github.com/livechat/lc-sdk-go/v2/configuration/bulk"
+)
+
+const agentsCSV = `email, name, role, groups, max_chats_count, work_scheduler
+john@example.com, John, normal, Sales:first;Support, 3, "monday 09:00-17:00, friday 09:00-13:00"
+jane@example.com, Jane, , , ,
+invalid, Nobody, , , ,
+mark@example.com, Mark, , , many,
+`
+
+func TestReadCSV(t *testing.T) {
+	rows, err := bulk.ReadCSV(strings.NewReader(agentsCSV))
+	if err != nil {
+		t.Fatalf("ReadCSV failed: %v", err)
+	}
+	if len(rows) != 4 {
+		t.Fatalf("invalid number of rows: %d", len(rows))
+	}
+
+	john := rows[0]
+	if john.Index != 1 || john.Email != "john@example.com" || john.Name != "John" || john.Role != "normal" || john.MaxChatsCount != 3 {
+		t.Errorf("invalid row: %+v", john)
+	}
+	if len(john.Groups) != 2 || john.Groups[0] != (bulk.GroupAssignment{Group: "Sales", Priority: configuration.First}) || john.Groups[1].Group != "Support" {
+		t.Errorf("invalid groups: %+v", john.Groups)
+	}
+	if john.WorkScheduler.String() != "monday 09:00-17:00, friday 09:00-13:00" {
+		t.Errorf("invalid work scheduler: %v", john.WorkScheduler)
+	}
+
+	for i, valid := range []bool{true, true, false, false} {
+		if err := rows[i].Validate(); (err == nil) != valid {
+			t.Errorf("invalid validation result of row %d: %v", i+1, err)
+		}
+	}
+}
+
+func TestReadCSVShouldRequireEmailAndName(t *testing.T) {
+	if _, err := bulk.ReadCSV(strings.NewReader("email,role\njohn@example.com,normal\n")); err == nil {
+		t.Errorf("ReadCSV should fail")
+	}
+}
+
+func TestReadJSON(t *testing.T) {
+	rows, err := bulk.ReadJSON(strings.NewReader(`[
+		{"email": "john@example.com", "name": "John", "groups": [{"group": "Sales", "priority": "last"}], "work_scheduler": {"monday": {"start": "09:00", "end": "17:00"}}},
+		{"email": "jane@example.com", "name": "Jane", "role": "owner"}
+	]`))
+	if err != nil {
+		t.Fatalf("ReadJSON failed: %v", err)
+	}
+	if len(rows) != 2 || rows[1].Index != 2 {
+		t.Fatalf("invalid rows: %+v", rows)
+	}
+	if err := rows[0].Validate(); err != nil {
+		t.Errorf("valid row rejected: %v", err)
+	}
+	if err := rows[1].Validate(); err == nil {
+		t.Errorf("owner role should be rejected")
+	}
+}
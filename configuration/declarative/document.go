@@ -0,0 +1,154 @@
+// Package declarative allows to manage LiveChat license configuration as code.
+//
+// Desired configuration (groups, agents, bots, webhooks and properties) is described with Document,
+// which can be loaded from JSON or YAML. Plan compares the Document with current state of the license
+// and returns list of changes, which can be printed for review and then applied. Applying a Document
+// is idempotent - planning it again after successful Apply results in an empty Plan.
+//
+// Only resources described in the Document are managed. Groups, agents and bots missing in the Document
+// are left intact, while webhooks owned by Document's ClientID and missing in the Document are unregistered.
+package declarative
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/livechat/lc-sdk-go/v2/configuration"
+)
+
+// Document describes desired configuration of a license.
+type Document struct {
+	// ClientID identifies application owning webhooks described in the Document. It is required if the
+	// Document describes webhooks.
+	ClientID   string                                   `json:"client_id,omitempty"`
+	Groups     []*Group                                 `json:"groups,omitempty"`
+	Agents     []*Agent                                 `json:"agents,omitempty"`
+	Bots       []*Bot                                   `json:"bots,omitempty"`
+	Webhooks   []*configuration.Webhook                 `json:"webhooks,omitempty"`
+	Properties map[string]*configuration.PropertyConfig `json:"properties,omitempty"`
+}
+
+// Group describes desired group. Groups are identified by name.
+type Group struct {
+	Name            string                                 `json:"name"`
+	LanguageCode    string                                 `json:"language_code,omitempty"`
+	AgentPriorities map[string]configuration.GroupPriority `json:"agent_priorities,omitempty"`
+}
+
+// GroupAssignment describes membership of an agent or a bot in a group referenced by name.
+type GroupAssignment struct {
+	Group    string                      `json:"group"`
+	Priority configuration.GroupPriority `json:"priority"`
+}
+
+// Agent describes desired agent. Agents are identified by ID (ie. login).
+//
+// Only fields set in the Document are managed.
+type Agent struct {
+	ID            string                      `json:"id"`
+	Name          string                      `json:"name,omitempty"`
+	Role          string                      `json:"role,omitempty"`
+	JobTitle      string                      `json:"job_title,omitempty"`
+	Mobile        string                      `json:"mobile,omitempty"`
+	MaxChatsCount uint                        `json:"max_chats_count,omitempty"`
+	Groups        []GroupAssignment           `json:"groups,omitempty"`
+	WorkScheduler configuration.WorkScheduler `json:"work_scheduler,omitempty"`
+}
+
+// Bot describes desired bot owned by the application. Bots are identified by name.
+type Bot struct {
+	Name                 string                      `json:"name"`
+	Avatar               string                      `json:"avatar,omitempty"`
+	MaxChatsCount        uint                        `json:"max_chats_count,omitempty"`
+	DefaultGroupPriority configuration.GroupPriority `json:"default_group_priority,omitempty"`
+	Groups               []GroupAssignment           `json:"groups,omitempty"`
+	Webhooks             *configuration.BotWebhooks  `json:"webhooks,omitempty"`
+}
+
+// UnmarshalFunc decodes document data into given value.
+type UnmarshalFunc func(data []byte, v interface{}) error
+
+// Load decodes Document from data with given unmarshal function and validates it.
+//
+// If unmarshal is nil, data is decoded as JSON. YAML documents can be loaded with any
+// YAML decoder honoring json struct tags, ie. Unmarshal function of sigs.k8s.io/yaml.
+func Load(data []byte, unmarshal UnmarshalFunc) (*Document, error) {
+	if unmarshal == nil {
+		unmarshal = json.Unmarshal
+	}
+	var doc Document
+	if err := unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("couldn't decode document: %v", err)
+	}
+	if err := doc.Validate(); err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+// Validate checks if Document is complete and consistent.
+func (d *Document) Validate() error {
+	groups := make(map[string]bool, len(d.Groups))
+	for _, g := range d.Groups {
+		if g == nil || g.Name == "" {
+			return fmt.Errorf("group name cannot be empty")
+		}
+		if groups[g.Name] {
+			return fmt.Errorf("duplicated group: %q", g.Name)
+		}
+		groups[g.Name] = true
+	}
+
+	agents := make(map[string]bool, len(d.Agents))
+	for _, a := range d.Agents {
+		if a == nil || a.ID == "" {
+			return fmt.Errorf("agent ID cannot be empty")
+		}
+		if agents[a.ID] {
+			return fmt.Errorf("duplicated agent: %q", a.ID)
+		}
+		agents[a.ID] = true
+	}
+
+	bots := make(map[string]bool, len(d.Bots))
+	for _, b := range d.Bots {
+		if b == nil || b.Name == "" {
+			return fmt.Errorf("bot name cannot be empty")
+		}
+		if bots[b.Name] {
+			return fmt.Errorf("duplicated bot: %q", b.Name)
+		}
+		bots[b.Name] = true
+		for _, ga := range b.Groups {
+			if ga.Priority == configuration.DoNotAssign {
+				return fmt.Errorf("bot %q: DoNotAssign priority is allowed only as default group priority", b.Name)
+			}
+		}
+	}
+
+	if len(d.Webhooks) > 0 && d.ClientID == "" {
+		return fmt.Errorf("client ID is required to manage webhooks")
+	}
+	webhooks := make(map[string]bool, len(d.Webhooks))
+	for _, w := range d.Webhooks {
+		if w == nil || w.Action == "" || w.URL == "" {
+			return fmt.Errorf("webhook action and URL cannot be empty")
+		}
+		key := webhookKey(string(w.Action), w.URL)
+		if webhooks[key] {
+			return fmt.Errorf("duplicated webhook: %s", key)
+		}
+		webhooks[key] = true
+	}
+
+	for name, p := range d.Properties {
+		if p == nil || p.Type == "" {
+			return fmt.Errorf("property %q: type cannot be empty", name)
+		}
+	}
+	return nil
+}
+
+func webhookKey(action, url string) string {
+	return action + " " + url
+}
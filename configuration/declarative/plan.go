@@ -0,0 +1,592 @@
+package declarative
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/livechat/lc-sdk-go/v2/configuration"
+)
+
+// API is a subset of configuration.API used to plan and apply Document.
+type API interface {
+	ListGroups(fields []string) ([]*configuration.Group, error)
+	CreateGroup(name, language string, agentPriorities map[string]configuration.GroupPriority) (int32, error)
+	UpdateGroup(id int32, name, language string, agentPriorities map[string]configuration.GroupPriority) error
+	ListAgents(groupIDs []int32, fields []string) ([]*configuration.Agent, error)
+	CreateAgent(id string, fields *configuration.AgentFields) (string, error)
+	UpdateAgent(id string, fields *configuration.AgentFields) error
+	ListBots(getAll bool) ([]*configuration.BotAgent, error)
+	GetBot(id string) (*configuration.BotAgentDetails, error)
+	CreateBot(name, avatar string, status configuration.BotStatus, maxChats uint, defaultPriority configuration.GroupPriority, groups []*configuration.GroupConfig, webhooks *configuration.BotWebhooks) (string, error)
+	UpdateBot(id, name, avatar string, status configuration.BotStatus, maxChats uint, defaultPriority configuration.GroupPriority, groups []*configuration.GroupConfig, webhooks *configuration.BotWebhooks) error
+	ListRegisteredWebhooks() ([]configuration.RegisteredWebhook, error)
+	RegisterWebhook(webhook *configuration.Webhook) (string, error)
+	UnregisterWebhook(id string) error
+	RegisterProperties(properties map[string]*configuration.PropertyConfig) error
+	SyncProperties(desired map[string]*configuration.PropertyConfig, dryRun bool) (*configuration.PropertySyncResult, error)
+}
+
+// Operation describes kind of a Change.
+type Operation string
+
+// Possible values of Operation.
+const (
+	Create Operation = "create"
+	Update Operation = "update"
+	Delete Operation = "delete"
+)
+
+// Change describes single modification of license configuration.
+type Change struct {
+	// Resource is one of: group, agent, bot, webhook, property.
+	Resource  string
+	Operation Operation
+	Name      string
+	// Details lists differing fields of updated resources.
+	Details []string
+
+	apply func(api API, s *state) error
+}
+
+func (c *Change) String() string {
+	symbol := map[Operation]string{Create: "+", Update: "~", Delete: "-"}[c.Operation]
+	line := fmt.Sprintf("%s %s %q", symbol, c.Resource, c.Name)
+	if len(c.Details) > 0 {
+		line += "\n    " + strings.Join(c.Details, "\n    ")
+	}
+	return line
+}
+
+// Plan contains changes needed to bring license configuration to the state described by Document.
+type Plan struct {
+	Changes []*Change
+
+	groupIDs map[string]int32
+}
+
+// Empty returns true if license configuration already matches the Document.
+func (p *Plan) Empty() bool {
+	return len(p.Changes) == 0
+}
+
+// String returns printable diff of the Plan.
+func (p *Plan) String() string {
+	if p.Empty() {
+		return "No changes.\n"
+	}
+	var b strings.Builder
+	for _, c := range p.Changes {
+		b.WriteString(c.String())
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// Apply executes changes of the Plan in order. It stops on the first failed change.
+//
+// A Plan should be applied only once - in order to reconcile again, compute a new Plan.
+func (p *Plan) Apply(api API) error {
+	s := &state{groupIDs: make(map[string]int32, len(p.groupIDs))}
+	for name, id := range p.groupIDs {
+		s.groupIDs[name] = id
+	}
+	for _, c := range p.Changes {
+		if err := c.apply(api, s); err != nil {
+			return fmt.Errorf("couldn't %s %s %q: %v", c.Operation, c.Resource, c.Name, err)
+		}
+	}
+	return nil
+}
+
+// Apply plans Document against current state of the license and applies the resulting Plan.
+func Apply(api API, doc *Document) (*Plan, error) {
+	p, err := NewPlan(api, doc)
+	if err != nil {
+		return nil, err
+	}
+	return p, p.Apply(api)
+}
+
+type state struct {
+	groupIDs map[string]int32
+}
+
+func (s *state) groupConfigs(assignments []GroupAssignment) ([]*configuration.GroupConfig, error) {
+	configs := make([]*configuration.GroupConfig, 0, len(assignments))
+	for _, ga := range assignments {
+		id, exists := s.groupIDs[ga.Group]
+		if !exists {
+			return nil, fmt.Errorf("unknown group: %q", ga.Group)
+		}
+		configs = append(configs, &configuration.GroupConfig{ID: uint(id), Priority: ga.Priority})
+	}
+	return configs, nil
+}
+
+// NewPlan compares Document with current state of the license and returns changes needed to apply it.
+func NewPlan(api API, doc *Document) (*Plan, error) {
+	if err := doc.Validate(); err != nil {
+		return nil, err
+	}
+	p := &Plan{groupIDs: make(map[string]int32)}
+
+	steps := []func(API, *Document) error{p.planGroups, p.checkGroupAssignments, p.planAgents, p.planBots, p.planWebhooks, p.planProperties}
+	for _, step := range steps {
+		if err := step(api, doc); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+func (p *Plan) add(c *Change) {
+	p.Changes = append(p.Changes, c)
+}
+
+func fieldDiff(field string, current, desired interface{}) string {
+	return fmt.Sprintf("%s: %v -> %v", field, current, desired)
+}
+
+func (p *Plan) planGroups(api API, doc *Document) error {
+	if len(doc.Groups) == 0 && len(doc.Agents) == 0 && len(doc.Bots) == 0 {
+		return nil
+	}
+	groups, err := api.ListGroups([]string{"agent_priorities"})
+	if err != nil {
+		return fmt.Errorf("couldn't list groups: %v", err)
+	}
+	current := make(map[string]*configuration.Group, len(groups))
+	for _, g := range groups {
+		current[g.Name] = g
+		p.groupIDs[g.Name] = int32(g.ID)
+	}
+
+	for _, g := range doc.Groups {
+		g := g
+		existing, exists := current[g.Name]
+		if !exists {
+			p.add(&Change{
+				Resource:  "group",
+				Operation: Create,
+				Name:      g.Name,
+				apply: func(api API, s *state) error {
+					id, err := api.CreateGroup(g.Name, g.LanguageCode, g.AgentPriorities)
+					if err != nil {
+						return err
+					}
+					s.groupIDs[g.Name] = id
+					return nil
+				},
+			})
+			continue
+		}
+
+		var details []string
+		language, priorities := existing.LanguageCode, existing.AgentPriorities
+		if g.LanguageCode != "" && g.LanguageCode != existing.LanguageCode {
+			details = append(details, fieldDiff("language_code", existing.LanguageCode, g.LanguageCode))
+			language = g.LanguageCode
+		}
+		if g.AgentPriorities != nil && !reflect.DeepEqual(g.AgentPriorities, existing.AgentPriorities) {
+			details = append(details, fieldDiff("agent_priorities", existing.AgentPriorities, g.AgentPriorities))
+			priorities = g.AgentPriorities
+		}
+		if len(details) == 0 {
+			continue
+		}
+		id := int32(existing.ID)
+		p.add(&Change{
+			Resource:  "group",
+			Operation: Update,
+			Name:      g.Name,
+			Details:   details,
+			apply: func(api API, s *state) error {
+				return api.UpdateGroup(id, g.Name, language, priorities)
+			},
+		})
+	}
+	return nil
+}
+
+// checkGroupAssignments verifies that agents and bots are assigned only to existing groups or groups created
+// by the Document, so that the Plan doesn't fail after some of its changes are already applied.
+func (p *Plan) checkGroupAssignments(api API, doc *Document) error {
+	known := make(map[string]bool, len(p.groupIDs)+len(doc.Groups))
+	for name := range p.groupIDs {
+		known[name] = true
+	}
+	for _, g := range doc.Groups {
+		known[g.Name] = true
+	}
+	for _, a := range doc.Agents {
+		for _, ga := range a.Groups {
+			if !known[ga.Group] {
+				return fmt.Errorf("agent %q: unknown group: %q", a.ID, ga.Group)
+			}
+		}
+	}
+	for _, b := range doc.Bots {
+		for _, ga := range b.Groups {
+			if !known[ga.Group] {
+				return fmt.Errorf("bot %q: unknown group: %q", b.Name, ga.Group)
+			}
+		}
+	}
+	return nil
+}
+
+// sameGroups checks if assignments match group configs. Assignments to groups which
+// don't exist yet are always considered different.
+func (p *Plan) sameGroups(assignments []GroupAssignment, configs []configuration.GroupConfig) bool {
+	if len(assignments) != len(configs) {
+		return false
+	}
+	current := make(map[uint]configuration.GroupPriority, len(configs))
+	for _, gc := range configs {
+		current[gc.ID] = gc.Priority
+	}
+	for _, ga := range assignments {
+		id, exists := p.groupIDs[ga.Group]
+		if !exists {
+			return false
+		}
+		if priority, assigned := current[uint(id)]; !assigned || priority != ga.Priority {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *Plan) groupNames(configs []configuration.GroupConfig) []GroupAssignment {
+	names := make(map[int32]string, len(p.groupIDs))
+	for name, id := range p.groupIDs {
+		names[id] = name
+	}
+	assignments := make([]GroupAssignment, 0, len(configs))
+	for _, gc := range configs {
+		name, exists := names[int32(gc.ID)]
+		if !exists {
+			name = fmt.Sprintf("#%d", gc.ID)
+		}
+		assignments = append(assignments, GroupAssignment{Group: name, Priority: gc.Priority})
+	}
+	return assignments
+}
+
+func (p *Plan) planAgents(api API, doc *Document) error {
+	if len(doc.Agents) == 0 {
+		return nil
+	}
+	agents, err := api.ListAgents(nil, []string{"name", "role", "job_title", "mobile", "max_chats_count", "groups", "work_scheduler"})
+	if err != nil {
+		return fmt.Errorf("couldn't list agents: %v", err)
+	}
+	current := make(map[string]*configuration.AgentFields, len(agents))
+	for _, a := range agents {
+		if a.AgentFields == nil {
+			a.AgentFields = &configuration.AgentFields{}
+		}
+		current[a.ID] = a.AgentFields
+	}
+
+	for _, a := range doc.Agents {
+		a := a
+		apply := func(update bool) func(API, *state) error {
+			return func(api API, s *state) error {
+				fields, err := a.fields(s)
+				if err != nil {
+					return err
+				}
+				if update {
+					return api.UpdateAgent(a.ID, fields)
+				}
+				_, err = api.CreateAgent(a.ID, fields)
+				return err
+			}
+		}
+
+		existing, exists := current[a.ID]
+		if !exists {
+			p.add(&Change{Resource: "agent", Operation: Create, Name: a.ID, apply: apply(false)})
+			continue
+		}
+
+		var details []string
+		for _, f := range []struct {
+			name             string
+			desired, current string
+		}{
+			{"name", a.Name, existing.Name},
+			{"role", a.Role, existing.Role},
+			{"job_title", a.JobTitle, existing.JobTitle},
+			{"mobile", a.Mobile, existing.Mobile},
+		} {
+			if f.desired != "" && f.desired != f.current {
+				details = append(details, fieldDiff(f.name, f.current, f.desired))
+			}
+		}
+		if a.MaxChatsCount != 0 && a.MaxChatsCount != existing.MaxChatsCount {
+			details = append(details, fieldDiff("max_chats_count", existing.MaxChatsCount, a.MaxChatsCount))
+		}
+		if a.Groups != nil && !p.sameGroups(a.Groups, existing.Groups) {
+			details = append(details, fieldDiff("groups", p.groupNames(existing.Groups), a.Groups))
+		}
+		if a.WorkScheduler != nil && !reflect.DeepEqual(a.WorkScheduler, existing.WorkScheduler) {
+			details = append(details, fieldDiff("work_scheduler", existing.WorkScheduler, a.WorkScheduler))
+		}
+		if len(details) > 0 {
+			p.add(&Change{Resource: "agent", Operation: Update, Name: a.ID, Details: details, apply: apply(true)})
+		}
+	}
+	return nil
+}
+
+func (a *Agent) fields(s *state) (*configuration.AgentFields, error) {
+	fields := &configuration.AgentFields{
+		Name:          a.Name,
+		Role:          a.Role,
+		JobTitle:      a.JobTitle,
+		Mobile:        a.Mobile,
+		MaxChatsCount: a.MaxChatsCount,
+		WorkScheduler: a.WorkScheduler,
+	}
+	if a.Groups != nil {
+		configs, err := s.groupConfigs(a.Groups)
+		if err != nil {
+			return nil, err
+		}
+		fields.Groups = make([]configuration.GroupConfig, 0, len(configs))
+		for _, gc := range configs {
+			fields.Groups = append(fields.Groups, *gc)
+		}
+	}
+	return fields, nil
+}
+
+func (p *Plan) planBots(api API, doc *Document) error {
+	if len(doc.Bots) == 0 {
+		return nil
+	}
+	bots, err := api.ListBots(false)
+	if err != nil {
+		return fmt.Errorf("couldn't list bots: %v", err)
+	}
+	ids := make(map[string]string, len(bots))
+	for _, b := range bots {
+		ids[b.Name] = b.ID
+	}
+
+	for _, b := range doc.Bots {
+		b := b
+		id, exists := ids[b.Name]
+		if !exists {
+			p.add(&Change{
+				Resource:  "bot",
+				Operation: Create,
+				Name:      b.Name,
+				apply: func(api API, s *state) error {
+					groups, err := s.groupConfigs(b.Groups)
+					if err != nil {
+						return err
+					}
+					_, err = api.CreateBot(b.Name, b.Avatar, "", b.MaxChatsCount, b.DefaultGroupPriority, groups, b.Webhooks)
+					return err
+				},
+			})
+			continue
+		}
+
+		existing, err := api.GetBot(id)
+		if err != nil {
+			return fmt.Errorf("couldn't get bot %q: %v", b.Name, err)
+		}
+		desired := *b
+		var details []string
+		if b.Avatar != "" && b.Avatar != existing.Avatar {
+			details = append(details, fieldDiff("avatar", existing.Avatar, b.Avatar))
+		} else {
+			desired.Avatar = existing.Avatar
+		}
+		if b.MaxChatsCount != 0 && b.MaxChatsCount != existing.MaxChatsCount {
+			details = append(details, fieldDiff("max_chats_count", existing.MaxChatsCount, b.MaxChatsCount))
+		} else {
+			desired.MaxChatsCount = existing.MaxChatsCount
+		}
+		if b.DefaultGroupPriority != "" && b.DefaultGroupPriority != existing.DefaultGroupPriority {
+			details = append(details, fieldDiff("default_group_priority", existing.DefaultGroupPriority, b.DefaultGroupPriority))
+		} else {
+			desired.DefaultGroupPriority = existing.DefaultGroupPriority
+		}
+		currentGroups := make([]configuration.GroupConfig, 0, len(existing.Groups))
+		for _, gc := range existing.Groups {
+			currentGroups = append(currentGroups, *gc)
+		}
+		if b.Groups != nil && !p.sameGroups(b.Groups, currentGroups) {
+			details = append(details, fieldDiff("groups", p.groupNames(currentGroups), b.Groups))
+		}
+		if b.Webhooks != nil && !reflect.DeepEqual(b.Webhooks, existing.Webhooks) {
+			details = append(details, fieldDiff("webhooks", describeBotWebhooks(existing.Webhooks), describeBotWebhooks(b.Webhooks)))
+		} else {
+			desired.Webhooks = existing.Webhooks
+		}
+		if len(details) == 0 {
+			continue
+		}
+		p.add(&Change{
+			Resource:  "bot",
+			Operation: Update,
+			Name:      b.Name,
+			Details:   details,
+			apply: func(api API, s *state) error {
+				groups := existing.Groups
+				if desired.Groups != nil {
+					configs, err := s.groupConfigs(desired.Groups)
+					if err != nil {
+						return err
+					}
+					groups = configs
+				}
+				return api.UpdateBot(id, desired.Name, desired.Avatar, "", desired.MaxChatsCount, desired.DefaultGroupPriority, groups, desired.Webhooks)
+			},
+		})
+	}
+	return nil
+}
+
+func describeBotWebhooks(w *configuration.BotWebhooks) string {
+	if w == nil {
+		return "none"
+	}
+	actions := make([]string, 0, len(w.Actions))
+	for _, a := range w.Actions {
+		actions = append(actions, string(a.Name))
+	}
+	return fmt.Sprintf("%s %v", w.URL, actions)
+}
+
+// describeWebhookDifference formats difference like fieldDiff, without revealing secret keys.
+func describeWebhookDifference(d configuration.WebhookDifference) string {
+	switch d.Field {
+	case "secret_key":
+		return "secret_key: changed"
+	case "filters":
+		registered, _ := json.Marshal(d.Registered)
+		desired, _ := json.Marshal(d.Desired)
+		return fieldDiff(d.Field, string(registered), string(desired))
+	}
+	return fieldDiff(d.Field, d.Registered, d.Desired)
+}
+
+func (p *Plan) planWebhooks(api API, doc *Document) error {
+	if len(doc.Webhooks) == 0 && doc.ClientID == "" {
+		return nil
+	}
+	registered, err := api.ListRegisteredWebhooks()
+	if err != nil {
+		return fmt.Errorf("couldn't list webhooks: %v", err)
+	}
+	current := make(map[string]configuration.RegisteredWebhook, len(registered))
+	for _, rw := range registered {
+		// Webhooks of other applications are never updated nor unregistered.
+		if rw.OwnerClientID != doc.ClientID {
+			continue
+		}
+		current[webhookKey(rw.Action, rw.URL)] = rw
+	}
+
+	for _, w := range doc.Webhooks {
+		w := w
+		key := webhookKey(string(w.Action), w.URL)
+		register := func(api API, s *state) error {
+			_, err := api.RegisterWebhook(w)
+			return err
+		}
+
+		existing, exists := current[key]
+		if !exists {
+			p.add(&Change{Resource: "webhook", Operation: Create, Name: key, apply: register})
+			continue
+		}
+		delete(current, key)
+
+		diff := w.Differences(existing)
+		if len(diff) == 0 {
+			continue
+		}
+		details := make([]string, 0, len(diff))
+		for _, d := range diff {
+			details = append(details, describeWebhookDifference(d))
+		}
+		id := existing.ID
+		p.add(&Change{
+			Resource:  "webhook",
+			Operation: Update,
+			Name:      key,
+			Details:   details,
+			// New version is registered first, so that no events are lost if unregistering fails.
+			apply: func(api API, s *state) error {
+				if err := register(api, s); err != nil {
+					return err
+				}
+				return api.UnregisterWebhook(id)
+			},
+		})
+	}
+
+	if doc.ClientID == "" {
+		return nil
+	}
+	stale := make([]string, 0, len(current))
+	for key := range current {
+		stale = append(stale, key)
+	}
+	sort.Strings(stale)
+	for _, key := range stale {
+		id := current[key].ID
+		p.add(&Change{
+			Resource:  "webhook",
+			Operation: Delete,
+			Name:      key,
+			apply: func(api API, s *state) error {
+				return api.UnregisterWebhook(id)
+			},
+		})
+	}
+	return nil
+}
+
+func (p *Plan) planProperties(api API, doc *Document) error {
+	if len(doc.Properties) == 0 {
+		return nil
+	}
+	result, err := api.SyncProperties(doc.Properties, true)
+	if err != nil {
+		return fmt.Errorf("couldn't list properties: %v", err)
+	}
+	planned := make(map[string]bool)
+	for _, d := range result.Drift {
+		// Properties are registered in application's namespace, so drift in several namespaces needs one change.
+		if planned[d.Name] {
+			continue
+		}
+		planned[d.Name] = true
+		name, config := d.Name, d.Desired
+		c := &Change{
+			Resource:  "property",
+			Operation: Create,
+			Name:      name,
+			apply: func(api API, s *state) error {
+				return api.RegisterProperties(map[string]*configuration.PropertyConfig{name: config})
+			},
+		}
+		if d.Kind == configuration.PropertyChanged {
+			c.Operation = Update
+			c.Details = d.Fields
+		}
+		p.add(c)
+	}
+	return nil
+}
@@ -0,0 +1,338 @@
+package declarative_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/livechat/lc-sdk-go/v2/configuration"
+	"github.com/livechat/lc-sdk-go/v2/configuration/declarative"
+)
+
+var _ declarative.API = &configuration.API{}
+
+type fakeAPI struct {
+	groups     []*configuration.Group
+	agents     map[string]*configuration.AgentFields
+	bots       map[string]*configuration.BotAgentDetails
+	webhooks   []configuration.RegisteredWebhook
+	properties map[string]map[string]*configuration.PropertyConfig
+	lastID     int
+	// registerErr is returned by RegisterWebhook if set.
+	registerErr error
+}
+
+func newFakeAPI() *fakeAPI {
+	return &fakeAPI{
+		groups:     []*configuration.Group{{ID: 0, Name: "General", LanguageCode: "en"}},
+		agents:     map[string]*configuration.AgentFields{"john@example.com": {Name: "John", MaxChatsCount: 3}},
+		bots:       make(map[string]*configuration.BotAgentDetails),
+		properties: map[string]map[string]*configuration.PropertyConfig{"client": {}},
+		webhooks: []configuration.RegisteredWebhook{
+			{ID: "stale", Action: "incoming_chat", URL: "https://old.example.com", OwnerClientID: "client"},
+			{ID: "foreign", Action: "incoming_chat", URL: "https://other.example.com", OwnerClientID: "other"},
+		},
+	}
+}
+
+func (f *fakeAPI) nextID() int {
+	f.lastID++
+	return f.lastID
+}
+
+func (f *fakeAPI) ListGroups(fields []string) ([]*configuration.Group, error) {
+	return f.groups, nil
+}
+
+func (f *fakeAPI) CreateGroup(name, language string, agentPriorities map[string]configuration.GroupPriority) (int32, error) {
+	id := f.nextID()
+	f.groups = append(f.groups, &configuration.Group{ID: id, Name: name, LanguageCode: language, AgentPriorities: agentPriorities})
+	return int32(id), nil
+}
+
+func (f *fakeAPI) UpdateGroup(id int32, name, language string, agentPriorities map[string]configuration.GroupPriority) error {
+	for _, g := range f.groups {
+		if g.ID == int(id) {
+			g.Name, g.LanguageCode, g.AgentPriorities = name, language, agentPriorities
+			return nil
+		}
+	}
+	return fmt.Errorf("group not found")
+}
+
+func (f *fakeAPI) ListAgents(groupIDs []int32, fields []string) ([]*configuration.Agent, error) {
+	var agents []*configuration.Agent
+	for id, fields := range f.agents {
+		copied := *fields
+		agents = append(agents, &configuration.Agent{ID: id, AgentFields: &copied})
+	}
+	return agents, nil
+}
+
+func (f *fakeAPI) CreateAgent(id string, fields *configuration.AgentFields) (string, error) {
+	f.agents[id] = fields
+	return id, nil
+}
+
+func (f *fakeAPI) UpdateAgent(id string, fields *configuration.AgentFields) error {
+	a := f.agents[id]
+	if fields.Name != "" {
+		a.Name = fields.Name
+	}
+	if fields.MaxChatsCount != 0 {
+		a.MaxChatsCount = fields.MaxChatsCount
+	}
+	if fields.Groups != nil {
+		a.Groups = fields.Groups
+	}
+	return nil
+}
+
+func (f *fakeAPI) ListBots(getAll bool) ([]*configuration.BotAgent, error) {
+	var bots []*configuration.BotAgent
+	for _, b := range f.bots {
+		bot := b.BotAgent
+		bots = append(bots, &bot)
+	}
+	return bots, nil
+}
+
+func (f *fakeAPI) GetBot(id string) (*configuration.BotAgentDetails, error) {
+	return f.bots[id], nil
+}
+
+func (f *fakeAPI) CreateBot(name, avatar string, status configuration.BotStatus, maxChats uint, defaultPriority configuration.GroupPriority, groups []*configuration.GroupConfig, webhooks *configuration.BotWebhooks) (string, error) {
+	id := fmt.Sprintf("bot-%d", f.nextID())
+	return id, f.UpdateBot(id, name, avatar, status, maxChats, defaultPriority, groups, webhooks)
+}
+
+func (f *fakeAPI) UpdateBot(id, name, avatar string, status configuration.BotStatus, maxChats uint, defaultPriority configuration.GroupPriority, groups []*configuration.GroupConfig, webhooks *configuration.BotWebhooks) error {
+	bot := &configuration.BotAgentDetails{
+		BotAgent:             configuration.BotAgent{ID: id, Name: name, Avatar: avatar},
+		DefaultGroupPriority: defaultPriority,
+		MaxChatsCount:        maxChats,
+		Groups:               groups,
+		Webhooks:             webhooks,
+	}
+	f.bots[id] = bot
+	return nil
+}
+
+func (f *fakeAPI) ListRegisteredWebhooks() ([]configuration.RegisteredWebhook, error) {
+	return f.webhooks, nil
+}
+
+func (f *fakeAPI) RegisterWebhook(w *configuration.Webhook) (string, error) {
+	if f.registerErr != nil {
+		return "", f.registerErr
+	}
+	id := fmt.Sprintf("webhook-%d", f.nextID())
+	f.webhooks = append(f.webhooks, configuration.RegisteredWebhook{
+		ID:             id,
+		Action:         string(w.Action),
+		SecretKey:      w.SecretKey,
+		URL:            w.URL,
+		AdditionalData: w.AdditionalData,
+		Description:    w.Description,
+		Filters:        w.Filters,
+		OwnerClientID:  "client",
+	})
+	return id, nil
+}
+
+func (f *fakeAPI) UnregisterWebhook(id string) error {
+	for i, w := range f.webhooks {
+		if w.ID == id {
+			f.webhooks = append(f.webhooks[:i], f.webhooks[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("webhook not found")
+}
+
+func (f *fakeAPI) RegisterProperties(properties map[string]*configuration.PropertyConfig) error {
+	for name, p := range properties {
+		f.properties["client"][name] = p
+	}
+	return nil
+}
+
+func (f *fakeAPI) SyncProperties(desired map[string]*configuration.PropertyConfig, dryRun bool) (*configuration.PropertySyncResult, error) {
+	return &configuration.PropertySyncResult{Drift: configuration.DiffProperties(desired, f.properties)}, nil
+}
+
+const document = `{
+	"client_id": "client",
+	"groups": [
+		{"name": "General", "language_code": "pl"},
+		{"name": "Sales", "language_code": "en"}
+	],
+	"agents": [
+		{"id": "john@example.com", "max_chats_count": 5, "groups": [{"group": "Sales", "priority": "first"}]},
+		{"id": "jane@example.com", "name": "Jane", "role": "normal"}
+	],
+	"bots": [
+		{"name": "Greeter", "max_chats_count": 10, "default_group_priority": "supervisor", "groups": [{"group": "Sales", "priority": "normal"}]}
+	],
+	"webhooks": [
+		{"action": "incoming_chat", "url": "https://app.example.com/webhooks", "secret_key": "secret"}
+	],
+	"properties": {
+		"score": {"type": "int", "range": {"from": 0, "to": 10}}
+	}
+}`
+
+func TestLoadShouldRejectInvalidDocuments(t *testing.T) {
+	for _, data := range []string{
+		`{"groups": [{"name": "A"}, {"name": "A"}]}`,
+		`{"agents": [{"name": "no id"}]}`,
+		`{"bots": [{"name": "B", "groups": [{"group": "A", "priority": "supervisor"}]}]}`,
+		`{"webhooks": [{"action": "incoming_chat"}]}`,
+		`{"webhooks": [{"action": "incoming_chat", "url": "https://app.example.com/webhooks"}]}`,
+		`{"properties": {"p": {}}}`,
+		`not a document`,
+	} {
+		if _, err := declarative.Load([]byte(data), nil); err == nil {
+			t.Errorf("document should be rejected: %s", data)
+		}
+	}
+}
+
+func TestPlanShouldDescribeChanges(t *testing.T) {
+	doc, err := declarative.Load([]byte(document), nil)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	plan, err := declarative.NewPlan(newFakeAPI(), doc)
+	if err != nil {
+		t.Fatalf("NewPlan failed: %v", err)
+	}
+
+	expected := []string{
+		`~ group "General"`,
+		`    language_code: en -> pl`,
+		`+ group "Sales"`,
+		`~ agent "john@example.com"`,
+		`    max_chats_count: 3 -> 5`,
+		`+ agent "jane@example.com"`,
+		`+ bot "Greeter"`,
+		`+ webhook "incoming_chat https://app.example.com/webhooks"`,
+		`- webhook "incoming_chat https://old.example.com"`,
+		`+ property "score"`,
+	}
+	printed := plan.String()
+	for _, line := range expected {
+		if !strings.Contains(printed, line+"\n") {
+			t.Errorf("plan should contain %q, got:\n%s", line, printed)
+		}
+	}
+	if len(plan.Changes) != 8 {
+		t.Errorf("invalid number of changes: %v", len(plan.Changes))
+	}
+}
+
+func TestApplyShouldBeIdempotent(t *testing.T) {
+	api := newFakeAPI()
+	doc, err := declarative.Load([]byte(document), nil)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if _, err := declarative.Apply(api, doc); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	if groups := api.agents["john@example.com"].Groups; len(groups) != 1 || groups[0].ID != 1 {
+		t.Errorf("invalid agent groups: %v", groups)
+	}
+	if len(api.webhooks) != 2 || api.webhooks[0].ID != "foreign" {
+		t.Errorf("invalid webhooks: %v", api.webhooks)
+	}
+
+	plan, err := declarative.NewPlan(api, doc)
+	if err != nil {
+		t.Fatalf("NewPlan failed: %v", err)
+	}
+	if !plan.Empty() {
+		t.Errorf("plan should be empty after apply, got:\n%s", plan)
+	}
+}
+
+func TestApplyShouldKeepWebhookWhenUpdateFails(t *testing.T) {
+	api := newFakeAPI()
+	api.webhooks[0].URL = "https://app.example.com/webhooks"
+	api.registerErr = fmt.Errorf("boom")
+	doc, err := declarative.Load([]byte(`{"client_id": "client", "webhooks": [{"action": "incoming_chat", "url": "https://app.example.com/webhooks", "secret_key": "secret"}]}`), nil)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if _, err := declarative.Apply(api, doc); err == nil {
+		t.Fatalf("Apply should fail")
+	}
+	if len(api.webhooks) != 2 || api.webhooks[0].ID != "stale" {
+		t.Errorf("previous webhook should be kept: %v", api.webhooks)
+	}
+}
+
+func TestPlanShouldDescribeWebhookUpdates(t *testing.T) {
+	api := newFakeAPI()
+	api.webhooks[0].URL = "https://app.example.com/webhooks"
+	api.webhooks[0].Description = "old"
+	doc, err := declarative.Load([]byte(`{"client_id": "client", "webhooks": [{"action": "incoming_chat", "url": "https://app.example.com/webhooks", "secret_key": "secret", "description": "new"}]}`), nil)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	plan, err := declarative.NewPlan(api, doc)
+	if err != nil {
+		t.Fatalf("NewPlan failed: %v", err)
+	}
+	printed := plan.String()
+	for _, line := range []string{"    secret_key: changed\n", "    description: old -> new\n"} {
+		if !strings.Contains(printed, line) {
+			t.Errorf("plan should contain %q, got:\n%s", line, printed)
+		}
+	}
+}
+
+func TestApplyShouldNotTouchWebhooksOfOtherApplications(t *testing.T) {
+	api := newFakeAPI()
+	doc, err := declarative.Load([]byte(`{"client_id": "client", "webhooks": [{"action": "incoming_chat", "url": "https://other.example.com", "secret_key": "secret"}]}`), nil)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	plan, err := declarative.Apply(api, doc)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if !strings.Contains(plan.String(), `+ webhook "incoming_chat https://other.example.com"`) {
+		t.Errorf("webhook should be created, got:\n%s", plan)
+	}
+	if len(api.webhooks) != 2 || api.webhooks[0].ID != "foreign" || api.webhooks[0].OwnerClientID != "other" {
+		t.Errorf("webhook of other application should be kept: %v", api.webhooks)
+	}
+}
+
+func TestApplyShouldRejectUnknownGroupsBeforeAnyChange(t *testing.T) {
+	api := newFakeAPI()
+	doc, err := declarative.Load([]byte(`{
+		"groups": [{"name": "Sales", "language_code": "en"}],
+		"agents": [
+			{"id": "jane@example.com", "groups": [{"group": "Sales", "priority": "normal"}]},
+			{"id": "john@example.com", "max_chats_count": 5, "groups": [{"group": "Sale", "priority": "normal"}]}
+		]
+	}`), nil)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if _, err := declarative.Apply(api, doc); err == nil {
+		t.Fatalf("Apply should fail")
+	}
+	if len(api.groups) != 1 || len(api.agents) != 1 || api.agents["john@example.com"].MaxChatsCount != 3 {
+		t.Errorf("no changes should be applied: %v, %v", api.groups, api.agents)
+	}
+}
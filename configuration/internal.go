@@ -54,7 +54,7 @@ type listRegisteredPropertiesRequest struct {
 	All bool `json:"all"`
 }
 
-type listRegisteredPropertiesResponse map[string]*PropertyConfig
+type listRegisteredPropertiesResponse map[string]map[string]*PropertyConfig
 
 type getGroupRequest struct {
 	ID     int      `json:"id"`
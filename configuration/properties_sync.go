@@ -0,0 +1,205 @@
+package configuration
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// PropertyDriftKind describes how registered property differs from the desired one.
+type PropertyDriftKind string
+
+// Possible values of PropertyDriftKind.
+const (
+	PropertyMissing PropertyDriftKind = "missing"
+	PropertyChanged PropertyDriftKind = "changed"
+)
+
+// PropertyDrift describes difference between desired and registered configuration of a property.
+type PropertyDrift struct {
+	Name string
+	// Namespace of registered property. It is empty for missing properties.
+	Namespace string
+	Kind      PropertyDriftKind
+	// Fields lists differing parts of configuration (type, locations, domain, range) for changed properties.
+	Fields     []string
+	Desired    *PropertyConfig
+	Registered *PropertyConfig
+}
+
+func (d PropertyDrift) String() string {
+	if d.Kind == PropertyMissing {
+		return fmt.Sprintf("property %s is not registered", d.Name)
+	}
+	return fmt.Sprintf("property %s.%s differs in: %s", d.Namespace, d.Name, strings.Join(d.Fields, ", "))
+}
+
+// PropertySyncResult contains outcome of SyncProperties call.
+type PropertySyncResult struct {
+	// Drift lists all detected differences, sorted by property name and namespace.
+	Drift []PropertyDrift
+	// Applied contains configurations passed to RegisterProperties. It is empty in dry-run mode.
+	Applied map[string]*PropertyConfig
+}
+
+// DiffProperties compares desired property configurations with registered ones, grouped by namespace as
+// returned by ListRegisteredProperties, and returns detected drift sorted by property name and namespace.
+//
+// Property registered in several namespaces is compared with each of them. Properties registered but not
+// present in desired set are not reported.
+func DiffProperties(desired map[string]*PropertyConfig, registered map[string]map[string]*PropertyConfig) []PropertyDrift {
+	names := make([]string, 0, len(desired))
+	for name := range desired {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	namespaces := make([]string, 0, len(registered))
+	for namespace := range registered {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+
+	var drift []PropertyDrift
+	for _, name := range names {
+		want := desired[name]
+		found := false
+		for _, namespace := range namespaces {
+			have, exists := registered[namespace][name]
+			if !exists || have == nil {
+				continue
+			}
+			found = true
+			if fields := diffPropertyConfig(want, have); len(fields) > 0 {
+				drift = append(drift, PropertyDrift{
+					Name:       name,
+					Namespace:  namespace,
+					Kind:       PropertyChanged,
+					Fields:     fields,
+					Desired:    want,
+					Registered: have,
+				})
+			}
+		}
+		if !found {
+			drift = append(drift, PropertyDrift{
+				Name:    name,
+				Kind:    PropertyMissing,
+				Desired: want,
+			})
+		}
+	}
+	return drift
+}
+
+// SyncProperties reconciles properties registered by the application with desired configuration.
+//
+// Only missing or changed properties are passed to RegisterProperties. In dry-run mode drift is
+// reported, but nothing is registered.
+func (a *API) SyncProperties(desired map[string]*PropertyConfig, dryRun bool) (*PropertySyncResult, error) {
+	registered, err := a.ListRegisteredProperties(false)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &PropertySyncResult{
+		Drift:   DiffProperties(desired, registered),
+		Applied: make(map[string]*PropertyConfig),
+	}
+	if dryRun || len(result.Drift) == 0 {
+		return result, nil
+	}
+
+	toApply := make(map[string]*PropertyConfig, len(result.Drift))
+	for _, d := range result.Drift {
+		toApply[d.Name] = d.Desired
+	}
+	if err := a.RegisterProperties(toApply); err != nil {
+		return result, err
+	}
+	result.Applied = toApply
+	return result, nil
+}
+
+func diffPropertyConfig(want, have *PropertyConfig) []string {
+	if want == nil {
+		want = &PropertyConfig{}
+	}
+	var fields []string
+	if want.Type != have.Type {
+		fields = append(fields, "type")
+	}
+	if !equalLocations(want.Locations, have.Locations) {
+		fields = append(fields, "locations")
+	}
+	if !equalDomain(want.Domain, have.Domain) {
+		fields = append(fields, "domain")
+	}
+	if !equalRange(want.Range, have.Range) {
+		fields = append(fields, "range")
+	}
+	return fields
+}
+
+func equalLocations(a, b map[string]*Location) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for name, la := range a {
+		lb, exists := b[name]
+		if !exists {
+			return false
+		}
+		var accessA, accessB map[string]*PropertyAccess
+		if la != nil {
+			accessA = la.Access
+		}
+		if lb != nil {
+			accessB = lb.Access
+		}
+		if len(accessA) != len(accessB) {
+			return false
+		}
+		for userType, pa := range accessA {
+			pb, exists := accessB[userType]
+			if !exists || (pa == nil) != (pb == nil) || (pa != nil && *pa != *pb) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// equalDomain compares domains regardless of values order. Values are compared by their JSON
+// representation, since values decoded from API responses differ in Go types from the desired ones.
+func equalDomain(a, b []interface{}) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	normalize := func(values []interface{}) []string {
+		out := make([]string, 0, len(values))
+		for _, v := range values {
+			raw, err := json.Marshal(v)
+			if err != nil {
+				raw = []byte(fmt.Sprintf("%v", v))
+			}
+			out = append(out, string(raw))
+		}
+		sort.Strings(out)
+		return out
+	}
+	na, nb := normalize(a), normalize(b)
+	for i := range na {
+		if na[i] != nb[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func equalRange(a, b *PropertyRange) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,140 @@
+package configuration_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"testing"
+
+	"github.com/livechat/lc-sdk-go/v2/configuration"
+)
+
+func createRecordingResponder(t *testing.T, calls map[string][]byte) roundTripFunc {
+	return func(req *http.Request) *http.Response {
+		action := path.Base(req.URL.Path)
+		body, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			t.Errorf("couldn't read request body: %v", err)
+		}
+		calls[action] = body
+
+		return &http.Response{
+			StatusCode: 200,
+			Body:       ioutil.NopCloser(bytes.NewBufferString(mockedResponses[action])),
+			Header:     make(http.Header),
+		}
+	}
+}
+
+func desiredProperties() map[string]*configuration.PropertyConfig {
+	return map[string]*configuration.PropertyConfig{
+		"greeting": {
+			Type: "string",
+			Locations: map[string]*configuration.Location{
+				"chat": {Access: map[string]*configuration.PropertyAccess{
+					"customer": {Read: true, Write: true},
+					"agent":    {Read: true, Write: false},
+				}},
+			},
+			Domain: []interface{}{"hi", "hello"},
+		},
+		"scoring": {
+			Type: "int",
+			Locations: map[string]*configuration.Location{
+				"event": {Access: map[string]*configuration.PropertyAccess{
+					"agent": {Read: true, Write: true},
+				}},
+			},
+			Range: &configuration.PropertyRange{From: 0, To: 5},
+		},
+		"mood": {Type: "string"},
+	}
+}
+
+func TestDiffPropertiesReportsMissingAndChangedProperties(t *testing.T) {
+	registered := desiredProperties()
+	delete(registered, "mood")
+	registered["scoring"] = &configuration.PropertyConfig{
+		Type:   "int",
+		Range:  &configuration.PropertyRange{From: 0, To: 10},
+		Domain: []interface{}{1.0},
+	}
+
+	drift := configuration.DiffProperties(desiredProperties(), map[string]map[string]*configuration.PropertyConfig{"app": registered})
+	if len(drift) != 2 {
+		t.Fatalf("invalid drift: %v", drift)
+	}
+	if drift[0].Name != "mood" || drift[0].Kind != configuration.PropertyMissing {
+		t.Errorf("invalid mood drift: %v", drift[0])
+	}
+	if d := drift[1]; d.Name != "scoring" || d.Namespace != "app" || d.Kind != configuration.PropertyChanged || len(d.Fields) != 3 || d.Fields[0] != "locations" {
+		t.Errorf("invalid scoring drift: %v", d)
+	}
+}
+
+func TestDiffPropertiesIgnoresDomainOrderAndNumberTypes(t *testing.T) {
+	desired := map[string]*configuration.PropertyConfig{"p": {Type: "int", Domain: []interface{}{1, 2}}}
+	registered := map[string]map[string]*configuration.PropertyConfig{"app": {"p": {Type: "int", Domain: []interface{}{2.0, 1.0}}}}
+
+	if drift := configuration.DiffProperties(desired, registered); len(drift) != 0 {
+		t.Errorf("unexpected drift: %v", drift)
+	}
+}
+
+func TestDiffPropertiesComparesEachNamespace(t *testing.T) {
+	desired := map[string]*configuration.PropertyConfig{"p": {Type: "int"}}
+	registered := map[string]map[string]*configuration.PropertyConfig{
+		"a": {"p": {Type: "int"}},
+		"b": {"p": {Type: "string"}},
+	}
+
+	drift := configuration.DiffProperties(desired, registered)
+	if len(drift) != 1 || drift[0].Namespace != "b" || drift[0].Kind != configuration.PropertyChanged {
+		t.Errorf("invalid drift: %v", drift)
+	}
+}
+
+func TestSyncPropertiesShouldRegisterOnlyDriftedProperties(t *testing.T) {
+	calls := make(map[string][]byte)
+	api, err := configuration.NewAPI(stubTokenGetter, NewTestClient(createRecordingResponder(t, calls)), "client_id")
+	if err != nil {
+		t.Errorf("API creation failed")
+	}
+
+	result, rErr := api.SyncProperties(desiredProperties(), false)
+	if rErr != nil {
+		t.Fatalf("SyncProperties failed: %v", rErr)
+	}
+	if len(result.Drift) != 2 || len(result.Applied) != 2 {
+		t.Errorf("invalid sync result: %v", result)
+	}
+
+	var registered map[string]*configuration.PropertyConfig
+	if err := json.Unmarshal(calls["register_properties"], &registered); err != nil {
+		t.Fatalf("invalid register_properties request: %v", err)
+	}
+	if _, exists := registered["greeting"]; exists || len(registered) != 2 {
+		t.Errorf("invalid registered properties: %v", registered)
+	}
+}
+
+func TestSyncPropertiesInDryRunShouldNotRegisterProperties(t *testing.T) {
+	calls := make(map[string][]byte)
+	api, err := configuration.NewAPI(stubTokenGetter, NewTestClient(createRecordingResponder(t, calls)), "client_id")
+	if err != nil {
+		t.Errorf("API creation failed")
+	}
+
+	result, rErr := api.SyncProperties(desiredProperties(), true)
+	if rErr != nil {
+		t.Fatalf("SyncProperties failed: %v", rErr)
+	}
+	if len(result.Drift) != 2 || len(result.Applied) != 0 {
+		t.Errorf("invalid sync result: %v", result)
+	}
+	if _, called := calls["register_properties"]; called {
+		t.Errorf("register_properties should not be called in dry-run mode")
+	}
+}
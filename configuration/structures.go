@@ -93,10 +93,13 @@ type PropertyConfig struct {
 	Locations   map[string]*Location `json:"locations"`
 	Description string               `json:"description,omitempty"`
 	Domain      []interface{}        `json:"domain,omitempty"`
-	Range       *struct {
-		From int `json:"from"`
-		To   int `json:"to"`
-	} `json:"range,omitempty"`
+	Range       *PropertyRange       `json:"range,omitempty"`
+}
+
+// PropertyRange defines range of allowed values of an int property
+type PropertyRange struct {
+	From int `json:"from"`
+	To   int `json:"to"`
 }
 
 // Location represents property location
@@ -1,5 +1,11 @@
 package configuration
 
+import (
+	"strings"
+
+	"github.com/livechat/lc-sdk-go/v2/objects"
+)
+
 // BotStatus represents bot availability status
 type BotStatus string
 
@@ -12,6 +18,11 @@ const (
 	Offline BotStatus = "offline"
 )
 
+// RoutingStatus converts BotStatus to routing status accepted by agent.API's SetRoutingStatus.
+func (s BotStatus) RoutingStatus() objects.RoutingStatus {
+	return objects.RoutingStatus(strings.Replace(string(s), " ", "_", -1))
+}
+
 // WebhookAction represents allowed values for action name
 type WebhookAction string
 
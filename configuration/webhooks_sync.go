@@ -0,0 +1,148 @@
+package configuration
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// WebhookDifference describes field in which Webhook differs from the registered one.
+type WebhookDifference struct {
+	// Field is one of: url, secret_key, description, additional_data, filters.
+	Field      string
+	Registered interface{}
+	Desired    interface{}
+}
+
+// Differences returns fields (url, secret_key, description, additional_data, filters) in which Webhook
+// differs from the registered one, along with their registered and desired values. Action is not compared.
+func (w *Webhook) Differences(rw RegisteredWebhook) []WebhookDifference {
+	var diff []WebhookDifference
+	add := func(field string, registered, desired interface{}) {
+		diff = append(diff, WebhookDifference{Field: field, Registered: registered, Desired: desired})
+	}
+	if w.URL != rw.URL {
+		add("url", rw.URL, w.URL)
+	}
+	if w.SecretKey != rw.SecretKey {
+		add("secret_key", rw.SecretKey, w.SecretKey)
+	}
+	if w.Description != rw.Description {
+		add("description", rw.Description, w.Description)
+	}
+	if !sameStringSet(w.AdditionalData, rw.AdditionalData) {
+		add("additional_data", rw.AdditionalData, w.AdditionalData)
+	}
+	if desired, registered := normalizeFilters(w.Filters), normalizeFilters(rw.Filters); !reflect.DeepEqual(desired, registered) {
+		add("filters", registered, desired)
+	}
+	return diff
+}
+
+// WebhookSyncPlan describes changes needed to reconcile registered webhooks with desired ones.
+type WebhookSyncPlan struct {
+	// Register contains webhooks which are missing or changed.
+	Register []*Webhook
+	// Unregister contains IDs of stale webhooks, including previous versions of changed ones.
+	Unregister []string
+	// Unchanged maps actions of up to date webhooks to their IDs.
+	Unchanged map[WebhookAction]string
+}
+
+// PlanWebhookSync compares desired webhooks with registered ones owned by given client ID.
+//
+// Webhooks are matched by action, so at most one webhook per action can be desired. Registered webhooks
+// owned by other applications are ignored.
+func PlanWebhookSync(ownerClientID string, desired []*Webhook, registered []RegisteredWebhook) (*WebhookSyncPlan, error) {
+	wanted := make(map[WebhookAction]*Webhook, len(desired))
+	for _, w := range desired {
+		if _, exists := wanted[w.Action]; exists {
+			return nil, fmt.Errorf("duplicated webhook action: %s", w.Action)
+		}
+		wanted[w.Action] = w
+	}
+
+	owned := make(map[WebhookAction][]RegisteredWebhook)
+	for _, rw := range registered {
+		if rw.OwnerClientID == ownerClientID {
+			action := WebhookAction(rw.Action)
+			owned[action] = append(owned[action], rw)
+		}
+	}
+
+	plan := &WebhookSyncPlan{Unchanged: make(map[WebhookAction]string)}
+	for _, w := range desired {
+		current := owned[w.Action]
+		if len(current) == 1 && len(w.Differences(current[0])) == 0 {
+			plan.Unchanged[w.Action] = current[0].ID
+			continue
+		}
+		plan.Register = append(plan.Register, w)
+	}
+	for action, current := range owned {
+		if _, unchanged := plan.Unchanged[action]; unchanged {
+			continue
+		}
+		for _, rw := range current {
+			plan.Unregister = append(plan.Unregister, rw.ID)
+		}
+	}
+	sort.Strings(plan.Unregister)
+	return plan, nil
+}
+
+// SyncWebhooks reconciles webhooks registered by application with given client ID with desired ones.
+//
+// Missing and changed webhooks are registered before stale ones are unregistered, so that no events are
+// lost during the update. It returns IDs of desired webhooks mapped by their actions.
+func (a *API) SyncWebhooks(ownerClientID string, desired []*Webhook) (map[WebhookAction]string, error) {
+	registered, err := a.ListRegisteredWebhooks()
+	if err != nil {
+		return nil, err
+	}
+	plan, err := PlanWebhookSync(ownerClientID, desired, registered)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make(map[WebhookAction]string, len(desired))
+	for action, id := range plan.Unchanged {
+		ids[action] = id
+	}
+	for _, w := range plan.Register {
+		id, err := a.RegisterWebhook(w)
+		if err != nil {
+			return ids, fmt.Errorf("couldn't register %s webhook: %v", w.Action, err)
+		}
+		ids[w.Action] = id
+	}
+	for _, id := range plan.Unregister {
+		if err := a.UnregisterWebhook(id); err != nil {
+			return ids, fmt.Errorf("couldn't unregister webhook %s: %v", id, err)
+		}
+	}
+	return ids, nil
+}
+
+func normalizeFilters(f *WebhookFilters) *WebhookFilters {
+	if f == nil {
+		return &WebhookFilters{}
+	}
+	return f
+}
+
+func sameStringSet(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	sa := append([]string(nil), a...)
+	sb := append([]string(nil), b...)
+	sort.Strings(sa)
+	sort.Strings(sb)
+	for i := range sa {
+		if sa[i] != sb[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,127 @@
+package configuration_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/livechat/lc-sdk-go/v2/configuration"
+)
+
+// threadClosed is used by mocked list_registered_webhooks response.
+const threadClosed = configuration.WebhookAction("thread_closed")
+
+func TestPlanWebhookSyncShouldKeepRegisterAndUnregisterWebhooks(t *testing.T) {
+	registered := []configuration.RegisteredWebhook{
+		{ID: "unchanged", Action: "incoming_chat", URL: "https://app.com", SecretKey: "s", AdditionalData: []string{"a", "b"}, OwnerClientID: "app"},
+		{ID: "changed", Action: "incoming_event", URL: "https://old.app.com", SecretKey: "s", OwnerClientID: "app"},
+		{ID: "stale", Action: "chat_deactivated", URL: "https://app.com", SecretKey: "s", OwnerClientID: "app"},
+		{ID: "foreign", Action: "thread_closed", URL: "https://other.com", SecretKey: "s", OwnerClientID: "other"},
+	}
+	desired := []*configuration.Webhook{
+		{Action: configuration.IncomingChat, URL: "https://app.com", SecretKey: "s", AdditionalData: []string{"b", "a"}, Filters: &configuration.WebhookFilters{}},
+		{Action: configuration.IncomingEvent, URL: "https://app.com", SecretKey: "s"},
+		{Action: threadClosed, URL: "https://app.com", SecretKey: "s"},
+	}
+
+	plan, err := configuration.PlanWebhookSync("app", desired, registered)
+	if err != nil {
+		t.Fatalf("PlanWebhookSync failed: %v", err)
+	}
+
+	if len(plan.Unchanged) != 1 || plan.Unchanged[configuration.IncomingChat] != "unchanged" {
+		t.Errorf("invalid unchanged webhooks: %v", plan.Unchanged)
+	}
+	if len(plan.Register) != 2 || plan.Register[0].Action != configuration.IncomingEvent || plan.Register[1].Action != threadClosed {
+		t.Errorf("invalid webhooks to register: %v", plan.Register)
+	}
+	if len(plan.Unregister) != 2 || plan.Unregister[0] != "changed" || plan.Unregister[1] != "stale" {
+		t.Errorf("invalid webhooks to unregister: %v", plan.Unregister)
+	}
+}
+
+func TestPlanWebhookSyncShouldRejectDuplicatedActions(t *testing.T) {
+	desired := []*configuration.Webhook{
+		{Action: configuration.IncomingChat, URL: "https://app.com"},
+		{Action: configuration.IncomingChat, URL: "https://other.app.com"},
+	}
+
+	if _, err := configuration.PlanWebhookSync("app", desired, nil); err == nil {
+		t.Errorf("duplicated actions should be rejected")
+	}
+}
+
+func TestWebhookDifferences(t *testing.T) {
+	w := &configuration.Webhook{Action: threadClosed, URL: "https://app.com", SecretKey: "new", Description: "Test webhook"}
+	rw := configuration.RegisteredWebhook{
+		URL:         "https://app.com",
+		SecretKey:   "old",
+		Description: "Test webhook",
+		Filters:     &configuration.WebhookFilters{AuthorType: "customer"},
+	}
+
+	diff := w.Differences(rw)
+	if len(diff) != 2 || diff[0].Field != "secret_key" || diff[1].Field != "filters" {
+		t.Fatalf("invalid differences: %v", diff)
+	}
+	if diff[0].Registered != "old" || diff[0].Desired != "new" {
+		t.Errorf("invalid secret_key difference: %v", diff[0])
+	}
+	if f, ok := diff[1].Registered.(*configuration.WebhookFilters); !ok || f.AuthorType != "customer" {
+		t.Errorf("invalid filters difference: %v", diff[1])
+	}
+}
+
+func TestSyncWebhooksShouldReplaceChangedWebhook(t *testing.T) {
+	calls := make(map[string][]byte)
+	api, err := configuration.NewAPI(stubTokenGetter, NewTestClient(createRecordingResponder(t, calls)), "client_id")
+	if err != nil {
+		t.Errorf("API creation failed")
+	}
+
+	ids, rErr := api.SyncWebhooks("asXdesldiAJSq9padj", []*configuration.Webhook{{
+		Action:    threadClosed,
+		URL:       "http://myservice.com/webhooks/v2",
+		SecretKey: "laudla991lamda0pnoaa0",
+	}})
+	if rErr != nil {
+		t.Fatalf("SyncWebhooks failed: %v", rErr)
+	}
+	if len(ids) != 1 || ids[threadClosed] != "pqi8oasdjahuakndw9nsad9na" {
+		t.Errorf("invalid webhook IDs: %v", ids)
+	}
+
+	var registered configuration.Webhook
+	if err := json.Unmarshal(calls["register_webhook"], &registered); err != nil || registered.URL != "http://myservice.com/webhooks/v2" {
+		t.Errorf("invalid register_webhook request: %s", calls["register_webhook"])
+	}
+	if string(calls["unregister_webhook"]) != `{"webhook_id":"pqi8oasdjahuakndw9nsad9na"}` {
+		t.Errorf("invalid unregister_webhook request: %s", calls["unregister_webhook"])
+	}
+}
+
+func TestSyncWebhooksShouldNotTouchUpToDateWebhook(t *testing.T) {
+	calls := make(map[string][]byte)
+	api, err := configuration.NewAPI(stubTokenGetter, NewTestClient(createRecordingResponder(t, calls)), "client_id")
+	if err != nil {
+		t.Errorf("API creation failed")
+	}
+
+	ids, rErr := api.SyncWebhooks("asXdesldiAJSq9padj", []*configuration.Webhook{{
+		Action:      threadClosed,
+		URL:         "http://myservice.com/webhooks",
+		Description: "Test webhook",
+		SecretKey:   "laudla991lamda0pnoaa0",
+		Filters: &configuration.WebhookFilters{
+			ChatMemberIDs: configuration.NewChatMemberIDsFilter([]string{"johndoe@mail.com"}, true),
+		},
+	}})
+	if rErr != nil {
+		t.Fatalf("SyncWebhooks failed: %v", rErr)
+	}
+	if ids[threadClosed] != "pqi8oasdjahuakndw9nsad9na" {
+		t.Errorf("invalid webhook IDs: %v", ids)
+	}
+	if len(calls) != 1 {
+		t.Errorf("only list_registered_webhooks should be called, got: %v", calls)
+	}
+}
@@ -0,0 +1,280 @@
+package configuration
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ParseWorkTime parses time of day in HH:MM format and returns it as duration since midnight.
+func ParseWorkTime(s string) (time.Duration, error) {
+	if len(s) != 5 || s[2] != ':' {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", s)
+	}
+	for _, i := range []int{0, 1, 3, 4} {
+		if s[i] < '0' || s[i] > '9' {
+			return 0, fmt.Errorf("invalid time %q, expected HH:MM", s)
+		}
+	}
+	hour := int(s[0]-'0')*10 + int(s[1]-'0')
+	minute := int(s[3]-'0')*10 + int(s[4]-'0')
+	if hour > 23 || minute > 59 {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", s)
+	}
+	return time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute, nil
+}
+
+// Validate checks if Start and End are in HH:MM format and differ.
+//
+// End earlier than Start means the shift ends on the next day.
+func (d WorkSchedulerDay) Validate() error {
+	start, err := ParseWorkTime(d.Start)
+	if err != nil {
+		return fmt.Errorf("start: %v", err)
+	}
+	end, err := ParseWorkTime(d.End)
+	if err != nil {
+		return fmt.Errorf("end: %v", err)
+	}
+	if start == end {
+		return fmt.Errorf("start and end cannot be equal")
+	}
+	return nil
+}
+
+var weekdays = []Weekday{Sunday, Monday, Tuesday, Wednesday, Thursday, Friday, Saturday}
+
+func weekdayOf(t time.Time) Weekday {
+	return weekdays[t.Weekday()]
+}
+
+// Validate checks if all days of WorkScheduler are valid.
+func (ws WorkScheduler) Validate() error {
+	for day, schedule := range ws {
+		valid := false
+		for _, wd := range weekdays {
+			valid = valid || wd == day
+		}
+		if !valid {
+			return fmt.Errorf("invalid weekday: %q", day)
+		}
+		if err := schedule.Validate(); err != nil {
+			return fmt.Errorf("%s: %v", day, err)
+		}
+	}
+	return nil
+}
+
+// Shift represents single period of work.
+type Shift struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Contains checks if t falls within the Shift.
+func (s Shift) Contains(t time.Time) bool {
+	return !t.Before(s.Start) && t.Before(s.End)
+}
+
+// shiftOn returns shift starting on the day of given date in given location.
+func (ws WorkScheduler) shiftOn(date time.Time, loc *time.Location) (Shift, bool, error) {
+	day, exists := ws[weekdayOf(date)]
+	if !exists {
+		return Shift{}, false, nil
+	}
+	if err := day.Validate(); err != nil {
+		return Shift{}, false, fmt.Errorf("%s: %v", weekdayOf(date), err)
+	}
+	start, _ := ParseWorkTime(day.Start)
+	end, _ := ParseWorkTime(day.End)
+
+	y, m, d := date.Date()
+	at := func(day int, offset time.Duration) time.Time {
+		return time.Date(y, m, day, int(offset/time.Hour), int(offset%time.Hour/time.Minute), 0, 0, loc)
+	}
+	s := Shift{Start: at(d, start), End: at(d, end)}
+	if end < start {
+		s.End = at(d+1, end)
+	}
+	return s, true, nil
+}
+
+// Shifts returns shifts overlapping [from, to) period, evaluated in given location.
+func (ws WorkScheduler) Shifts(from, to time.Time, loc *time.Location) ([]Shift, error) {
+	var shifts []Shift
+	y, m, d := from.In(loc).Date()
+	// Start a day earlier to include overnight shifts.
+	for i := -1; ; i++ {
+		date := time.Date(y, m, d+i, 0, 0, 0, 0, loc)
+		if !date.Before(to) {
+			break
+		}
+		s, exists, err := ws.shiftOn(date, loc)
+		if err != nil {
+			return nil, err
+		}
+		if exists && s.End.After(from) && s.Start.Before(to) {
+			shifts = append(shifts, s)
+		}
+	}
+	return shifts, nil
+}
+
+// OnDuty checks if t falls within WorkScheduler evaluated in given location.
+func (ws WorkScheduler) OnDuty(t time.Time, loc *time.Location) (bool, error) {
+	shifts, err := ws.Shifts(t, t.Add(time.Nanosecond), loc)
+	if err != nil {
+		return false, err
+	}
+	for _, s := range shifts {
+		if s.Contains(t) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// NextShiftStart returns start of the first shift beginning after t. It returns false if WorkScheduler is empty.
+func (ws WorkScheduler) NextShiftStart(t time.Time, loc *time.Location) (time.Time, bool, error) {
+	shifts, err := ws.Shifts(t, t.AddDate(0, 0, 8), loc)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	for _, s := range shifts {
+		if s.Start.After(t) {
+			return s.Start, true, nil
+		}
+	}
+	return time.Time{}, false, nil
+}
+
+// Overlap represents period in which given agents are on duty at the same time.
+type Overlap struct {
+	Shift
+	AgentIDs []string
+}
+
+// Overlaps returns periods within [from, to) in which at least two of given agents' schedules overlap.
+// Returned periods are sorted and have constant set of agents on duty.
+func Overlaps(schedules map[string]WorkScheduler, from, to time.Time, loc *time.Location) ([]Overlap, error) {
+	type edge struct {
+		at      time.Time
+		agentID string
+		delta   int
+	}
+	var edges []edge
+	for agentID, ws := range schedules {
+		shifts, err := ws.Shifts(from, to, loc)
+		if err != nil {
+			return nil, fmt.Errorf("agent %s: %v", agentID, err)
+		}
+		for _, s := range shifts {
+			if s.Start.Before(from) {
+				s.Start = from
+			}
+			if s.End.After(to) {
+				s.End = to
+			}
+			edges = append(edges, edge{s.Start, agentID, 1}, edge{s.End, agentID, -1})
+		}
+	}
+	sort.Slice(edges, func(i, j int) bool {
+		return edges[i].at.Before(edges[j].at)
+	})
+
+	var overlaps []Overlap
+	onDuty := make(map[string]int)
+	for i := 0; i < len(edges); {
+		at := edges[i].at
+		for ; i < len(edges) && edges[i].at.Equal(at); i++ {
+			onDuty[edges[i].agentID] += edges[i].delta
+			if onDuty[edges[i].agentID] == 0 {
+				delete(onDuty, edges[i].agentID)
+			}
+		}
+		if len(onDuty) < 2 || i == len(edges) {
+			continue
+		}
+		ids := make([]string, 0, len(onDuty))
+		for id := range onDuty {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		overlaps = append(overlaps, Overlap{Shift: Shift{Start: at, End: edges[i].at}, AgentIDs: ids})
+	}
+	return overlaps, nil
+}
+
+// NewWorkScheduler creates empty WorkScheduler.
+func NewWorkScheduler() WorkScheduler {
+	return make(WorkScheduler)
+}
+
+// With sets schedule of given days. Times are not validated - call Validate once the schedule is built.
+func (ws WorkScheduler) With(start, end string, days ...Weekday) WorkScheduler {
+	for _, day := range days {
+		ws[day] = WorkSchedulerDay{Start: start, End: end}
+	}
+	return ws
+}
+
+// WithWeekdays sets schedule of days from Monday to Friday.
+func (ws WorkScheduler) WithWeekdays(start, end string) WorkScheduler {
+	return ws.With(start, end, Monday, Tuesday, Wednesday, Thursday, Friday)
+}
+
+// Without removes schedule of given days.
+func (ws WorkScheduler) Without(days ...Weekday) WorkScheduler {
+	for _, day := range days {
+		delete(ws, day)
+	}
+	return ws
+}
+
+// ParseWorkScheduler parses WorkScheduler from format returned by its String method,
+// ie. "monday 09:00-17:00, friday 09:00-13:00". The result is validated.
+func ParseWorkScheduler(s string) (WorkScheduler, error) {
+	ws := NewWorkScheduler()
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		fields := strings.Fields(part)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("invalid day schedule %q, expected \"weekday HH:MM-HH:MM\"", part)
+		}
+		times := strings.Split(fields[1], "-")
+		if len(times) != 2 {
+			return nil, fmt.Errorf("invalid day schedule %q, expected \"weekday HH:MM-HH:MM\"", part)
+		}
+		day := Weekday(strings.ToLower(fields[0]))
+		if _, exists := ws[day]; exists {
+			return nil, fmt.Errorf("duplicated weekday: %q", day)
+		}
+		ws.With(times[0], times[1], day)
+	}
+	if err := ws.Validate(); err != nil {
+		return nil, err
+	}
+	return ws, nil
+}
+
+func (ws WorkScheduler) String() string {
+	parts := make([]string, 0, len(ws))
+	for _, day := range []Weekday{Monday, Tuesday, Wednesday, Thursday, Friday, Saturday, Sunday} {
+		if s, exists := ws[day]; exists {
+			parts = append(parts, fmt.Sprintf("%s %s-%s", day, s.Start, s.End))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// UpdateWorkScheduler validates given WorkScheduler and sets it as schedule of given agent.
+func (a *API) UpdateWorkScheduler(agentID string, ws WorkScheduler) error {
+	if err := ws.Validate(); err != nil {
+		return err
+	}
+	return a.UpdateAgent(agentID, &AgentFields{WorkScheduler: ws})
+}
@@ -0,0 +1,181 @@
+package configuration_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/livechat/lc-sdk-go/v2/configuration"
+)
+
+func TestParseWorkTime(t *testing.T) {
+	for input, expected := range map[string]time.Duration{
+		"00:00": 0,
+		"09:30": 9*time.Hour + 30*time.Minute,
+		"23:59": 23*time.Hour + 59*time.Minute,
+	} {
+		if d, err := configuration.ParseWorkTime(input); d != expected || err != nil {
+			t.Errorf("invalid time for %q: %v, %v", input, d, err)
+		}
+	}
+
+	for _, input := range []string{"", "9:30", "24:00", "12:60", "+1:00", "12-00", "12:00:00"} {
+		if _, err := configuration.ParseWorkTime(input); err == nil {
+			t.Errorf("invalid time %q should be rejected", input)
+		}
+	}
+}
+
+func TestWorkSchedulerValidate(t *testing.T) {
+	valid := configuration.NewWorkScheduler().WithWeekdays("09:00", "17:00").With("22:00", "06:00", configuration.Saturday)
+	if err := valid.Validate(); err != nil {
+		t.Errorf("valid schedule rejected: %v", err)
+	}
+
+	for _, ws := range []configuration.WorkScheduler{
+		{"someday": {Start: "09:00", End: "17:00"}},
+		{configuration.Monday: {Start: "9:00", End: "17:00"}},
+		{configuration.Monday: {Start: "09:00", End: "09:00"}},
+	} {
+		if err := ws.Validate(); err == nil {
+			t.Errorf("invalid schedule should be rejected: %v", ws)
+		}
+	}
+}
+
+func TestWorkSchedulerOnDuty(t *testing.T) {
+	loc := time.FixedZone("UTC-5", -5*60*60)
+	ws := configuration.NewWorkScheduler().
+		With("09:00", "17:00", configuration.Monday).
+		With("22:00", "06:00", configuration.Friday)
+
+	for _, tc := range []struct {
+		at     time.Time
+		onDuty bool
+	}{
+		// Monday, 09:00 in UTC-5.
+		{time.Date(2020, 1, 6, 14, 0, 0, 0, time.UTC), true},
+		// Monday, 17:00 in UTC-5.
+		{time.Date(2020, 1, 6, 22, 0, 0, 0, time.UTC), false},
+		// Monday, 08:59 in UTC-5.
+		{time.Date(2020, 1, 6, 13, 59, 0, 0, time.UTC), false},
+		// Saturday, 05:00 in UTC-5, during overnight shift started on Friday.
+		{time.Date(2020, 1, 11, 10, 0, 0, 0, time.UTC), true},
+		// Saturday, 06:00 in UTC-5.
+		{time.Date(2020, 1, 11, 11, 0, 0, 0, time.UTC), false},
+	} {
+		onDuty, err := ws.OnDuty(tc.at, loc)
+		if err != nil {
+			t.Errorf("OnDuty failed: %v", err)
+		}
+		if onDuty != tc.onDuty {
+			t.Errorf("invalid duty at %v: %v", tc.at.In(loc), onDuty)
+		}
+	}
+}
+
+func TestWorkSchedulerNextShiftStart(t *testing.T) {
+	ws := configuration.NewWorkScheduler().With("09:00", "17:00", configuration.Monday)
+
+	// Monday, 10:00.
+	next, ok, err := ws.NextShiftStart(time.Date(2020, 1, 6, 10, 0, 0, 0, time.UTC), time.UTC)
+	if err != nil || !ok {
+		t.Fatalf("NextShiftStart failed: %v, %v", ok, err)
+	}
+	if expected := time.Date(2020, 1, 13, 9, 0, 0, 0, time.UTC); !next.Equal(expected) {
+		t.Errorf("invalid next shift start: %v", next)
+	}
+
+	if _, ok, _ := configuration.NewWorkScheduler().NextShiftStart(time.Now(), time.UTC); ok {
+		t.Errorf("empty schedule should have no next shift")
+	}
+}
+
+func TestOverlaps(t *testing.T) {
+	schedules := map[string]configuration.WorkScheduler{
+		"a": configuration.NewWorkScheduler().With("08:00", "16:00", configuration.Monday),
+		"b": configuration.NewWorkScheduler().With("12:00", "20:00", configuration.Monday),
+		"c": configuration.NewWorkScheduler().With("14:00", "15:00", configuration.Monday),
+		"d": configuration.NewWorkScheduler().With("08:00", "16:00", configuration.Tuesday),
+	}
+	monday := time.Date(2020, 1, 6, 0, 0, 0, 0, time.UTC)
+
+	overlaps, err := configuration.Overlaps(schedules, monday, monday.AddDate(0, 0, 1), time.UTC)
+	if err != nil {
+		t.Fatalf("Overlaps failed: %v", err)
+	}
+
+	expected := []struct {
+		start, end int
+		agents     string
+	}{
+		{12, 14, `["a","b"]`},
+		{14, 15, `["a","b","c"]`},
+		{15, 16, `["a","b"]`},
+	}
+	if len(overlaps) != len(expected) {
+		t.Fatalf("invalid overlaps: %v", overlaps)
+	}
+	for i, e := range expected {
+		agents, _ := json.Marshal(overlaps[i].AgentIDs)
+		if overlaps[i].Start.Hour() != e.start || overlaps[i].End.Hour() != e.end || string(agents) != e.agents {
+			t.Errorf("invalid overlap %d: %v", i, overlaps[i])
+		}
+	}
+}
+
+func TestUpdateWorkSchedulerShouldSendScheduleViaUpdateAgent(t *testing.T) {
+	calls := make(map[string][]byte)
+	api, err := configuration.NewAPI(stubTokenGetter, NewTestClient(createRecordingResponder(t, calls)), "client_id")
+	if err != nil {
+		t.Errorf("API creation failed")
+	}
+
+	ws := configuration.NewWorkScheduler().WithWeekdays("09:00", "17:00").Without(configuration.Friday)
+	if rErr := api.UpdateWorkScheduler("smith@example.com", ws); rErr != nil {
+		t.Errorf("UpdateWorkScheduler failed: %v", rErr)
+	}
+
+	var payload struct {
+		ID            string                      `json:"id"`
+		WorkScheduler configuration.WorkScheduler `json:"work_scheduler"`
+	}
+	if err := json.Unmarshal(calls["update_agent"], &payload); err != nil {
+		t.Fatalf("couldn't unmarshal update_agent payload: %v", err)
+	}
+	if payload.ID != "smith@example.com" || len(payload.WorkScheduler) != 4 || payload.WorkScheduler[configuration.Monday].Start != "09:00" {
+		t.Errorf("invalid update_agent payload: %s", calls["update_agent"])
+	}
+}
+
+func TestUpdateWorkSchedulerShouldRejectInvalidSchedule(t *testing.T) {
+	calls := make(map[string][]byte)
+	api, err := configuration.NewAPI(stubTokenGetter, NewTestClient(createRecordingResponder(t, calls)), "client_id")
+	if err != nil {
+		t.Errorf("API creation failed")
+	}
+
+	ws := configuration.NewWorkScheduler().With("9", "17", configuration.Monday)
+	if rErr := api.UpdateWorkScheduler("smith@example.com", ws); rErr == nil {
+		t.Errorf("UpdateWorkScheduler should fail")
+	}
+	if len(calls) != 0 {
+		t.Errorf("invalid schedule should not be sent")
+	}
+}
+
+func TestParseWorkScheduler(t *testing.T) {
+	ws, err := configuration.ParseWorkScheduler("Monday 09:00-17:00, friday 22:00-06:00")
+	if err != nil {
+		t.Fatalf("ParseWorkScheduler failed: %v", err)
+	}
+	if s := ws.String(); s != "monday 09:00-17:00, friday 22:00-06:00" {
+		t.Errorf("invalid schedule: %v", s)
+	}
+
+	for _, input := range []string{"monday", "monday 09:00", "monday 9-17", "monday 09:00-17:00, monday 10:00-12:00"} {
+		if _, err := configuration.ParseWorkScheduler(input); err == nil {
+			t.Errorf("invalid schedule %q should be rejected", input)
+		}
+	}
+}
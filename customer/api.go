@@ -72,37 +72,26 @@ func (a *API) StartChat(initialChat *objects.InitialChat, continuous bool) (chat
 // SendMessage sends event of type message to given chat.
 // It returns event ID.
 func (a *API) SendMessage(chatID, text string, recipients Recipients) (string, error) {
-	e := objects.Message{
-		Event: objects.Event{
-			Type:       "message",
-			Recipients: string(recipients),
-		},
-		Text: text,
-	}
+	e := objects.NewMessage(text)
+	e.Recipients = string(recipients)
 
-	return a.SendEvent(chatID, &e, false)
+	return a.SendEvent(chatID, e, false)
 }
 
 // SendSystemMessage sends event of type system_message to given chat.
 // It returns event ID.
 func (a *API) SendSystemMessage(chatID, text, messageType string, textVars map[string]string, recipients Recipients, attachToLastThread bool) (string, error) {
-	e := objects.SystemMessage{
-		Event: objects.Event{
-			Type:       "system_message",
-			Recipients: string(recipients),
-		},
-		Text:     text,
-		Type:     messageType,
-		TextVars: textVars,
-	}
+	e := objects.NewSystemMessage(text, messageType).WithTextVars(textVars)
+	e.Recipients = string(recipients)
 
-	return a.SendEvent(chatID, &e, attachToLastThread)
+	return a.SendEvent(chatID, e, attachToLastThread)
 }
 
 // SendEvent sends event of supported type to given chat.
 // It returns event ID.
 //
-// Supported event types are: event, file, message, rich_message and system_message.
+// Supported event types are: event, custom, file, filled_form, message, rich_message and system_message.
+// Events are validated with objects.ValidateEvent before sending.
 func (a *API) SendEvent(chatID string, e interface{}, attachToLastThread bool) (string, error) {
 	if err := objects.ValidateEvent(e); err != nil {
 		return "", err
@@ -260,7 +249,11 @@ func (a *API) DeleteEventProperties(chatID, threadID, eventID string, properties
 }
 
 // UpdateCustomer updates current customer's info.
-func (a *API) UpdateCustomer(name, email, avatarURL string, sessionFields []map[string]string) error {
+// Session fields are validated before sending.
+func (a *API) UpdateCustomer(name, email, avatarURL string, sessionFields objects.SessionFields) error {
+	if err := sessionFields.Validate(); err != nil {
+		return err
+	}
 	return a.Call("update_customer", &updateCustomerRequest{
 		Name:          name,
 		Email:         email,
@@ -270,7 +263,11 @@ func (a *API) UpdateCustomer(name, email, avatarURL string, sessionFields []map[
 }
 
 // SetCustomerSessionFields sets current customer's fields.
-func (a *API) SetCustomerSessionFields(sessionFields []map[string]string) error {
+// Session fields are validated before sending.
+func (a *API) SetCustomerSessionFields(sessionFields objects.SessionFields) error {
+	if err := sessionFields.Validate(); err != nil {
+		return err
+	}
 	return a.Call("set_customer_session_fields", &setCustomerSessionFieldsRequest{
 		SessionFields: sessionFields,
 	}, &emptyResponse{})
@@ -0,0 +1,241 @@
+// Package auth obtains customer access tokens required by customer.API.
+//
+// Tokens are issued by LiveChat Accounts for a customer identity described by a Grant. Client caches them per
+// customer and refreshes them shortly before they expire, ie.
+//
+//	client := auth.NewClient("client_id", licenseID, nil)
+//	api, err := customer.NewAPI(client.TokenGetter(&auth.CookieGrant{}), nil, "client_id")
+//
+// Grants remember identity of the customer once the first token is issued, so subsequent tokens are issued for
+// the same customer.
+package auth
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/livechat/lc-sdk-go/v2/authorization"
+)
+
+// DefaultAccountsURL is the address of LiveChat Accounts.
+const DefaultAccountsURL = "https://accounts.livechat.com"
+
+// DefaultRefreshMargin is the default time before expiration at which cached tokens are refreshed.
+const DefaultRefreshMargin = 5 * time.Minute
+
+// Credentials represent customer access token issued by LiveChat Accounts.
+type Credentials struct {
+	CustomerID  string
+	AccessToken string
+	LicenseID   int
+	// Region is a datacenter the token was issued in, derived from its prefix (ie. "dal").
+	Region    string
+	ExpiresAt time.Time
+}
+
+// Token returns Credentials in form expected by Customer Chat API.
+func (c *Credentials) Token() *authorization.Token {
+	licenseID := c.LicenseID
+	return &authorization.Token{
+		LicenseID:   &licenseID,
+		AccessToken: c.AccessToken,
+		Region:      c.Region,
+		Type:        authorization.BearerToken,
+	}
+}
+
+// Client issues and caches customer access tokens.
+type Client struct {
+	httpClient    *http.Client
+	accountsURL   string
+	clientID      string
+	licenseID     int
+	redirectURI   string
+	refreshMargin time.Duration
+	now           func() time.Time
+	onError       func(error)
+
+	mu    sync.Mutex
+	cache map[string]*Credentials
+}
+
+// NewClient creates Client issuing tokens of given application for customers of given license.
+//
+// If provided client is nil, then default http client with 20s timeout is used.
+func NewClient(clientID string, licenseID int, client *http.Client) *Client {
+	if client == nil {
+		client = &http.Client{
+			Timeout: 20 * time.Second,
+		}
+	}
+	return &Client{
+		httpClient:    client,
+		accountsURL:   DefaultAccountsURL,
+		clientID:      clientID,
+		licenseID:     licenseID,
+		refreshMargin: DefaultRefreshMargin,
+		now:           time.Now,
+		cache:         make(map[string]*Credentials),
+	}
+}
+
+// WithAccountsURL replaces address of LiveChat Accounts, ie. with a local stand-in in tests.
+func (c *Client) WithAccountsURL(url string) *Client {
+	c.accountsURL = strings.TrimSuffix(url, "/")
+	return c
+}
+
+// WithRedirectURI sets redirect URI sent with token requests. It has to match one of application's redirect URIs
+// if the application restricts them.
+func (c *Client) WithRedirectURI(uri string) *Client {
+	c.redirectURI = uri
+	return c
+}
+
+// WithRefreshMargin sets time before expiration at which cached tokens are refreshed.
+func (c *Client) WithRefreshMargin(d time.Duration) *Client {
+	c.refreshMargin = d
+	return c
+}
+
+// WithClock replaces function used by Client to get current time.
+func (c *Client) WithClock(now func() time.Time) *Client {
+	c.now = now
+	return c
+}
+
+// WithErrorHandler sets function called with errors encountered by TokenGetters, which cannot return them.
+func (c *Client) WithErrorHandler(onError func(error)) *Client {
+	c.onError = onError
+	return c
+}
+
+// Token returns cached credentials of customer identified by grant or obtains new ones if they are missing or
+// about to expire.
+func (c *Client) Token(grant Grant) (*Credentials, error) {
+	grant.lock()
+	defer grant.unlock()
+
+	if creds := c.cached(grant.identity()); creds != nil {
+		return creds, nil
+	}
+
+	req, err := c.newRequest(grant)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't obtain customer token: %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't read token response: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var errResp errorResponse
+		if err := json.Unmarshal(body, &errResp); err != nil || errResp.Error == "" {
+			return nil, fmt.Errorf("couldn't obtain customer token (code: %d, raw body: %s)", resp.StatusCode, string(body))
+		}
+		return nil, fmt.Errorf("couldn't obtain customer token: %s - %s", errResp.Error, errResp.Description)
+	}
+
+	var tr tokenResponse
+	if err := json.Unmarshal(body, &tr); err != nil {
+		return nil, fmt.Errorf("couldn't unmarshal token response: %v", err)
+	}
+	if tr.AccessToken == "" || tr.EntityID == "" {
+		return nil, fmt.Errorf("token response is missing access token or customer ID")
+	}
+	creds := &Credentials{
+		CustomerID:  tr.EntityID,
+		AccessToken: tr.AccessToken,
+		LicenseID:   c.licenseID,
+		Region:      regionOf(tr.AccessToken),
+		ExpiresAt:   c.now().Add(time.Duration(tr.ExpiresIn) * time.Millisecond),
+	}
+	if tr.LicenseID != 0 {
+		creds.LicenseID = tr.LicenseID
+	}
+	grant.update(creds, resp)
+
+	c.mu.Lock()
+	c.cache[grant.identity()] = creds
+	c.mu.Unlock()
+	return creds, nil
+}
+
+// TokenGetter returns authorization.TokenGetter providing tokens of customer identified by grant.
+// Errors are passed to the handler set with WithErrorHandler and result in nil token.
+func (c *Client) TokenGetter(grant Grant) authorization.TokenGetter {
+	return func() *authorization.Token {
+		creds, err := c.Token(grant)
+		if err != nil {
+			if c.onError != nil {
+				c.onError(err)
+			}
+			return nil
+		}
+		return creds.Token()
+	}
+}
+
+// Invalidate removes cached token of customer identified by grant, ie. after it was revoked.
+func (c *Client) Invalidate(grant Grant) {
+	grant.lock()
+	defer grant.unlock()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.cache, grant.identity())
+}
+
+func (c *Client) cached(identity string) *Credentials {
+	if identity == "" {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	creds, exists := c.cache[identity]
+	if !exists || !c.now().Add(c.refreshMargin).Before(creds.ExpiresAt) {
+		return nil
+	}
+	return creds
+}
+
+func (c *Client) newRequest(grant Grant) (*http.Request, error) {
+	tr := &tokenRequest{
+		ClientID:     c.clientID,
+		LicenseID:    c.licenseID,
+		ResponseType: "token",
+		RedirectURI:  c.redirectURI,
+	}
+	grant.prepare(tr)
+	body, err := json.Marshal(tr)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest("POST", c.accountsURL+"/v2/customer/token", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("couldn't create new http request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if err := grant.authorize(req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// regionOf returns datacenter prefix of access token, ie. "dal" for "dal:abc".
+func regionOf(accessToken string) string {
+	if i := strings.Index(accessToken, ":"); i > 0 {
+		return accessToken[:i]
+	}
+	return ""
+}
@@ -0,0 +1,147 @@
+package auth_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/livechat/lc-sdk-go/v2/authorization"
+	"github.com/livechat/lc-sdk-go/v2/customer/auth"
+)
+
+type accountsStandIn struct {
+	mu       sync.Mutex
+	requests []map[string]interface{}
+	headers  []http.Header
+	issued   int
+}
+
+func (a *accountsStandIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req map[string]interface{}
+	json.NewDecoder(r.Body).Decode(&req)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.requests = append(a.requests, req)
+	a.headers = append(a.headers, r.Header)
+
+	if r.URL.Path != "/v2/customer/token" || req["client_id"] != "client_id" {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error": "invalid_request", "error_description": "invalid client"}`))
+		return
+	}
+
+	customerID := "new-customer"
+	switch req["grant_type"] {
+	case "cookie":
+		if c, err := r.Cookie(auth.CustomerIDCookie); err == nil {
+			customerID = c.Value
+		}
+		http.SetCookie(w, &http.Cookie{Name: auth.CustomerIDCookie, Value: customerID})
+		http.SetCookie(w, &http.Cookie{Name: auth.CustomerSecretCookie, Value: "secret"})
+	case "agent_token":
+		if r.Header.Get("Authorization") != "Bearer agent_token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error": "unauthorized", "error_description": "invalid agent token"}`))
+			return
+		}
+		if id, _ := req["customer_id"].(string); id != "" {
+			customerID = id
+		}
+	}
+	a.issued++
+	fmt.Fprintf(w, `{"access_token": "dal:token%d", "entity_id": %q, "expires_in": 28800000, "token_type": "Bearer", "license_id": 12345}`, a.issued, customerID)
+}
+
+func newTestClient(server *httptest.Server, now *time.Time) *auth.Client {
+	return auth.NewClient("client_id", 12345, nil).
+		WithAccountsURL(server.URL).
+		WithClock(func() time.Time { return *now })
+}
+
+func TestCookieGrantRemembersCustomer(t *testing.T) {
+	accounts := &accountsStandIn{}
+	server := httptest.NewServer(accounts)
+	defer server.Close()
+	now := time.Date(2020, 5, 7, 8, 0, 0, 0, time.UTC)
+	client := newTestClient(server, &now)
+
+	grant := &auth.CookieGrant{}
+	creds, err := client.Token(grant)
+	if err != nil {
+		t.Fatalf("Token failed: %v", err)
+	}
+	if creds.CustomerID != "new-customer" || creds.Region != "dal" || creds.LicenseID != 12345 || !creds.ExpiresAt.Equal(now.Add(8*time.Hour)) {
+		t.Errorf("invalid credentials: %+v", creds)
+	}
+	if grant.CustomerID != "new-customer" || grant.Secret != "secret" {
+		t.Errorf("grant should remember customer: %+v", grant)
+	}
+
+	if cached, _ := client.Token(grant); cached != creds || len(accounts.requests) != 1 {
+		t.Errorf("token should be cached")
+	}
+
+	now = now.Add(8*time.Hour - auth.DefaultRefreshMargin)
+	refreshed, err := client.Token(grant)
+	if err != nil {
+		t.Fatalf("Token failed: %v", err)
+	}
+	if refreshed.AccessToken != "dal:token2" || refreshed.CustomerID != "new-customer" {
+		t.Errorf("token should be refreshed for the same customer: %+v", refreshed)
+	}
+	if !strings.Contains(accounts.headers[1].Get("Cookie"), auth.CustomerSecretCookie+"=secret") {
+		t.Errorf("refresh should send customer cookies: %v", accounts.headers[1])
+	}
+}
+
+func TestAgentTokenGrantTokenGetter(t *testing.T) {
+	accounts := &accountsStandIn{}
+	server := httptest.NewServer(accounts)
+	defer server.Close()
+	now := time.Date(2020, 5, 7, 8, 0, 0, 0, time.UTC)
+	client := newTestClient(server, &now)
+
+	agentToken := func() *authorization.Token {
+		return &authorization.Token{AccessToken: "agent_token", Type: authorization.BearerToken}
+	}
+	getter := client.TokenGetter(&auth.AgentTokenGrant{AgentToken: agentToken, CustomerID: "b7eff798"})
+	token := getter()
+	if token == nil || token.AccessToken != "dal:token1" || *token.LicenseID != 12345 || token.Type != authorization.BearerToken {
+		t.Fatalf("invalid token: %+v", token)
+	}
+	if accounts.requests[0]["grant_type"] != "agent_token" || accounts.requests[0]["customer_id"] != "b7eff798" {
+		t.Errorf("invalid token request: %v", accounts.requests[0])
+	}
+
+	var errs []error
+	client.WithErrorHandler(func(err error) { errs = append(errs, err) })
+	invalidAgentToken := func() *authorization.Token {
+		return &authorization.Token{AccessToken: "expired", Type: authorization.BearerToken}
+	}
+	if token := client.TokenGetter(&auth.AgentTokenGrant{AgentToken: invalidAgentToken})(); token != nil {
+		t.Errorf("token should not be issued: %+v", token)
+	}
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "invalid agent token") {
+		t.Errorf("error should be reported: %v", errs)
+	}
+}
+
+func TestClientInvalidate(t *testing.T) {
+	accounts := &accountsStandIn{}
+	server := httptest.NewServer(accounts)
+	defer server.Close()
+	now := time.Date(2020, 5, 7, 8, 0, 0, 0, time.UTC)
+	client := newTestClient(server, &now)
+
+	grant := &auth.CookieGrant{CustomerID: "b7eff798", Secret: "secret"}
+	client.Token(grant)
+	client.Invalidate(grant)
+	if _, err := client.Token(grant); err != nil || len(accounts.requests) != 2 {
+		t.Errorf("invalidated token should be obtained again: %v", err)
+	}
+}
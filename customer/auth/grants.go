@@ -0,0 +1,137 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/livechat/lc-sdk-go/v2/authorization"
+)
+
+// Names of cookies identifying customer in cookie grant.
+const (
+	CustomerIDCookie     = "__lc_cid"
+	CustomerSecretCookie = "__lc_cst"
+)
+
+// Grant describes how customer access token is obtained and which customer it is issued for.
+// It is implemented by CookieGrant and AgentTokenGrant.
+type Grant interface {
+	lock()
+	unlock()
+	// identity returns key of customer in tokens cache or empty string if customer is not known yet.
+	identity() string
+	prepare(*tokenRequest)
+	authorize(*http.Request) error
+	update(*Credentials, *http.Response)
+}
+
+// CookieGrant obtains tokens the way chat widget does, identifying customer with a pair of cookies.
+//
+// Empty CookieGrant creates new customer. CustomerID and Secret are filled in once the token is issued and can
+// be persisted to restore customer's identity later.
+type CookieGrant struct {
+	CustomerID string
+	Secret     string
+
+	mu sync.Mutex
+}
+
+func (g *CookieGrant) lock()   { g.mu.Lock() }
+func (g *CookieGrant) unlock() { g.mu.Unlock() }
+
+func (g *CookieGrant) identity() string {
+	if g.CustomerID == "" {
+		return ""
+	}
+	return "cookie:" + g.CustomerID
+}
+
+func (g *CookieGrant) prepare(tr *tokenRequest) {
+	tr.GrantType = "cookie"
+}
+
+func (g *CookieGrant) authorize(req *http.Request) error {
+	if g.CustomerID != "" && g.Secret != "" {
+		req.AddCookie(&http.Cookie{Name: CustomerIDCookie, Value: g.CustomerID})
+		req.AddCookie(&http.Cookie{Name: CustomerSecretCookie, Value: g.Secret})
+	}
+	return nil
+}
+
+func (g *CookieGrant) update(creds *Credentials, resp *http.Response) {
+	g.CustomerID = creds.CustomerID
+	for _, cookie := range resp.Cookies() {
+		switch cookie.Name {
+		case CustomerIDCookie:
+			g.CustomerID = cookie.Value
+		case CustomerSecretCookie:
+			g.Secret = cookie.Value
+		}
+	}
+}
+
+// AgentTokenGrant obtains tokens on behalf of an agent, ie. in backend integrations acting as customers.
+//
+// Empty CustomerID creates new customer and is filled in once the token is issued.
+type AgentTokenGrant struct {
+	AgentToken authorization.TokenGetter
+	CustomerID string
+
+	mu sync.Mutex
+}
+
+func (g *AgentTokenGrant) lock()   { g.mu.Lock() }
+func (g *AgentTokenGrant) unlock() { g.mu.Unlock() }
+
+func (g *AgentTokenGrant) identity() string {
+	if g.CustomerID == "" {
+		return ""
+	}
+	return "agent_token:" + g.CustomerID
+}
+
+func (g *AgentTokenGrant) prepare(tr *tokenRequest) {
+	tr.GrantType = "agent_token"
+	tr.CustomerID = g.CustomerID
+}
+
+func (g *AgentTokenGrant) authorize(req *http.Request) error {
+	if g.AgentToken == nil {
+		return errors.New("agent token grant requires agent's TokenGetter")
+	}
+	token := g.AgentToken()
+	if token == nil {
+		return errors.New("couldn't get agent token")
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("%s %s", token.Type, token.AccessToken))
+	return nil
+}
+
+func (g *AgentTokenGrant) update(creds *Credentials, resp *http.Response) {
+	g.CustomerID = creds.CustomerID
+}
+
+type tokenRequest struct {
+	GrantType    string `json:"grant_type"`
+	ClientID     string `json:"client_id"`
+	ResponseType string `json:"response_type"`
+	LicenseID    int    `json:"license_id"`
+	RedirectURI  string `json:"redirect_uri,omitempty"`
+	CustomerID   string `json:"customer_id,omitempty"`
+}
+
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	EntityID    string `json:"entity_id"`
+	// ExpiresIn is expressed in milliseconds.
+	ExpiresIn int64  `json:"expires_in"`
+	TokenType string `json:"token_type"`
+	LicenseID int    `json:"license_id"`
+}
+
+type errorResponse struct {
+	Error       string `json:"error"`
+	Description string `json:"error_description"`
+}
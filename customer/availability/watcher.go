@@ -0,0 +1,243 @@
+// Package availability watches statuses of groups, ie. to decide whether chat button should be displayed.
+package availability
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/livechat/lc-sdk-go/v2/customer"
+)
+
+// API is a subset of customer.API used by Watcher.
+type API interface {
+	ListGroupStatuses(groupIDs []int) (map[int]customer.GroupStatus, error)
+}
+
+// Change represents change of group's status. Groups seen for the first time change from GroupStatusUnknown,
+// groups no longer returned by API change to GroupStatusUnknown.
+type Change struct {
+	GroupID int
+	From    customer.GroupStatus
+	To      customer.GroupStatus
+	At      time.Time
+}
+
+// Snapshot represents statuses of groups at given time.
+type Snapshot struct {
+	Groups    map[int]customer.GroupStatus
+	UpdatedAt time.Time
+}
+
+// snapshotResponse is Snapshot encoded by Handler, with statuses represented by their names.
+type snapshotResponse struct {
+	Groups    map[int]string `json:"groups"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+// DefaultJitter is the default fraction of polling interval by which Watcher randomly shifts polls, so many
+// watchers started at once don't poll at the same time.
+const DefaultJitter = 0.1
+
+// Watcher polls statuses of groups, caches them and notifies subscribers about changes.
+type Watcher struct {
+	api      API
+	groupIDs []int
+	jitter   float64
+	now      func() time.Time
+	rand     *rand.Rand
+
+	// pollMu serializes polls, so that results of concurrent polls are applied in order.
+	pollMu      sync.Mutex
+	mu          sync.Mutex
+	statuses    map[int]customer.GroupStatus
+	updatedAt   time.Time
+	subscribers map[int]func(Change)
+	nextID      int
+}
+
+// NewWatcher creates Watcher of given groups, or all groups if none are given.
+func NewWatcher(api API, groupIDs ...int) *Watcher {
+	return &Watcher{
+		api:         api,
+		groupIDs:    groupIDs,
+		jitter:      DefaultJitter,
+		now:         time.Now,
+		rand:        rand.New(rand.NewSource(time.Now().UnixNano())),
+		statuses:    make(map[int]customer.GroupStatus),
+		subscribers: make(map[int]func(Change)),
+	}
+}
+
+// WithJitter sets fraction (0-1) of polling interval by which polls are randomly shifted.
+func (w *Watcher) WithJitter(jitter float64) *Watcher {
+	w.jitter = jitter
+	return w
+}
+
+// WithClock replaces function used by Watcher to get current time.
+func (w *Watcher) WithClock(now func() time.Time) *Watcher {
+	w.now = now
+	return w
+}
+
+// Subscribe registers function called with every change of group's status. Returned function unregisters it.
+//
+// Subscribers are called synchronously by Poll, in order of group IDs, and should not block nor call Poll.
+func (w *Watcher) Subscribe(fn func(Change)) (unsubscribe func()) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	id := w.nextID
+	w.nextID++
+	w.subscribers[id] = fn
+	return func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		delete(w.subscribers, id)
+	}
+}
+
+// Poll fetches current statuses of groups, updates cache and notifies subscribers about changes.
+// Concurrent calls are serialized.
+func (w *Watcher) Poll() ([]Change, error) {
+	w.pollMu.Lock()
+	defer w.pollMu.Unlock()
+
+	statuses, err := w.api.ListGroupStatuses(w.groupIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	w.mu.Lock()
+	now := w.now()
+	var changes []Change
+	for id, status := range statuses {
+		if previous := w.statuses[id]; previous != status {
+			changes = append(changes, Change{GroupID: id, From: previous, To: status, At: now})
+		}
+	}
+	for id, previous := range w.statuses {
+		if _, exists := statuses[id]; !exists {
+			changes = append(changes, Change{GroupID: id, From: previous, To: customer.GroupStatusUnknown, At: now})
+		}
+	}
+	w.statuses = statuses
+	w.updatedAt = now
+	subscribers := make([]func(Change), 0, len(w.subscribers))
+	ids := make([]int, 0, len(w.subscribers))
+	for id := range w.subscribers {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	for _, id := range ids {
+		subscribers = append(subscribers, w.subscribers[id])
+	}
+	w.mu.Unlock()
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].GroupID < changes[j].GroupID })
+	for _, c := range changes {
+		for _, fn := range subscribers {
+			fn(c)
+		}
+	}
+	return changes, nil
+}
+
+// Run calls Poll every interval, shifted by jitter, until ctx is done. Poll errors are passed to onError,
+// if not nil. Cached statuses are kept when polling fails.
+//
+// It returns ctx.Err() once ctx is done, or an error immediately if interval is not positive.
+func (w *Watcher) Run(ctx context.Context, interval time.Duration, onError func(error)) error {
+	if interval <= 0 {
+		return fmt.Errorf("polling interval has to be positive, got %v", interval)
+	}
+	for {
+		if _, err := w.Poll(); err != nil && onError != nil {
+			onError(err)
+		}
+		timer := time.NewTimer(w.delay(interval))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (w *Watcher) delay(interval time.Duration) time.Duration {
+	spread := int64(w.jitter * float64(interval))
+	if spread <= 0 {
+		return interval
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return interval + time.Duration(w.rand.Int63n(2*spread+1)-spread)
+}
+
+// Status returns cached status of given group and information whether it is known.
+func (w *Watcher) Status(groupID int) (customer.GroupStatus, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	status, exists := w.statuses[groupID]
+	return status, exists
+}
+
+// Snapshot returns copy of cached statuses. UpdatedAt is zero if statuses weren't polled yet.
+func (w *Watcher) Snapshot() Snapshot {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	groups := make(map[int]customer.GroupStatus, len(w.statuses))
+	for id, status := range w.statuses {
+		groups[id] = status
+	}
+	return Snapshot{Groups: groups, UpdatedAt: w.updatedAt}
+}
+
+// Handler returns http.Handler serving cached Snapshot as JSON, ie.
+//
+//	{"groups": {"1": "online", "2": "offline"}, "updated_at": "2020-05-07T07:11:28.28834Z"}
+//
+// Snapshot can be limited to chosen groups with group_id query parameters. Service Unavailable is returned until
+// statuses are polled for the first time.
+func (w *Watcher) Handler() http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" && r.Method != "HEAD" {
+			rw.Header().Set("Allow", "GET, HEAD")
+			http.Error(rw, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		snapshot := w.Snapshot()
+		if snapshot.UpdatedAt.IsZero() {
+			http.Error(rw, "group statuses not available yet", http.StatusServiceUnavailable)
+			return
+		}
+		if ids := r.URL.Query()["group_id"]; len(ids) > 0 {
+			groups := make(map[int]customer.GroupStatus)
+			for _, raw := range ids {
+				id, err := strconv.Atoi(raw)
+				if err != nil {
+					http.Error(rw, "invalid group_id: "+raw, http.StatusBadRequest)
+					return
+				}
+				if status, exists := snapshot.Groups[id]; exists {
+					groups[id] = status
+				}
+			}
+			snapshot.Groups = groups
+		}
+
+		resp := snapshotResponse{Groups: make(map[int]string, len(snapshot.Groups)), UpdatedAt: snapshot.UpdatedAt}
+		for id, status := range snapshot.Groups {
+			resp.Groups[id] = status.String()
+		}
+		rw.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(rw).Encode(resp)
+	})
+}
@@ -0,0 +1,177 @@
+package availability_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/livechat/lc-sdk-go/v2/customer"
+	"github.com/livechat/lc-sdk-go/v2/customer/availability"
+)
+
+var _ availability.API = &customer.API{}
+
+type fakeAPI struct {
+	mu       sync.Mutex
+	statuses map[int]customer.GroupStatus
+	err      error
+	calls    int
+}
+
+func (f *fakeAPI) ListGroupStatuses(groupIDs []int) (map[int]customer.GroupStatus, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	statuses := make(map[int]customer.GroupStatus)
+	for id, s := range f.statuses {
+		statuses[id] = s
+	}
+	return statuses, nil
+}
+
+func TestWatcherPollNotifiesAboutChanges(t *testing.T) {
+	api := &fakeAPI{statuses: map[int]customer.GroupStatus{
+		0: customer.GroupStatusOnline,
+		1: customer.GroupStatusOnline,
+	}}
+	w := availability.NewWatcher(api)
+
+	var changes []availability.Change
+	unsubscribe := w.Subscribe(func(c availability.Change) { changes = append(changes, c) })
+
+	if _, err := w.Poll(); err != nil {
+		t.Fatalf("Poll failed: %v", err)
+	}
+	if len(changes) != 2 || changes[0].From != customer.GroupStatusUnknown || changes[0].To != customer.GroupStatusOnline {
+		t.Errorf("new groups should be reported: %+v", changes)
+	}
+
+	api.statuses = map[int]customer.GroupStatus{0: customer.GroupStatusOffline}
+	changes = nil
+	if _, err := w.Poll(); err != nil {
+		t.Fatalf("Poll failed: %v", err)
+	}
+	expected := []availability.Change{
+		{GroupID: 0, From: customer.GroupStatusOnline, To: customer.GroupStatusOffline},
+		{GroupID: 1, From: customer.GroupStatusOnline, To: customer.GroupStatusUnknown},
+	}
+	if len(changes) != len(expected) {
+		t.Fatalf("invalid changes: %+v", changes)
+	}
+	for i := range expected {
+		if changes[i].GroupID != expected[i].GroupID || changes[i].From != expected[i].From || changes[i].To != expected[i].To {
+			t.Errorf("invalid change %d: %+v", i, changes[i])
+		}
+	}
+	if status, ok := w.Status(0); !ok || status != customer.GroupStatusOffline {
+		t.Errorf("invalid cached status: %v, %v", status, ok)
+	}
+
+	unsubscribe()
+	api.statuses = map[int]customer.GroupStatus{0: customer.GroupStatusOnline}
+	changes = nil
+	if c, _ := w.Poll(); len(c) != 1 || len(changes) != 0 {
+		t.Errorf("unsubscribed function should not be called: %+v", changes)
+	}
+}
+
+func TestWatcherKeepsCacheOnError(t *testing.T) {
+	api := &fakeAPI{statuses: map[int]customer.GroupStatus{0: customer.GroupStatusOnline}}
+	w := availability.NewWatcher(api, 0)
+	w.Poll()
+
+	api.err = errors.New("boom")
+	if _, err := w.Poll(); err == nil {
+		t.Errorf("Poll should fail")
+	}
+	if status, ok := w.Status(0); !ok || status != customer.GroupStatusOnline {
+		t.Errorf("cached status should be kept: %v, %v", status, ok)
+	}
+}
+
+func TestWatcherRun(t *testing.T) {
+	api := &fakeAPI{err: errors.New("boom")}
+	w := availability.NewWatcher(api).WithJitter(0.5)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var mu sync.Mutex
+	errs := 0
+	done := make(chan error)
+	go func() {
+		done <- w.Run(ctx, time.Millisecond, func(error) {
+			mu.Lock()
+			errs++
+			if errs == 3 {
+				cancel()
+			}
+			mu.Unlock()
+		})
+	}()
+
+	select {
+	case err := <-done:
+		if err != context.Canceled {
+			t.Errorf("Run should return context error: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Run should return after context is cancelled")
+	}
+	if errs != 3 {
+		t.Errorf("errors should be passed to onError: %v", errs)
+	}
+
+	calls := api.calls
+	if err := w.Run(context.Background(), 0, nil); err == nil {
+		t.Errorf("Run should reject non-positive interval")
+	}
+	if api.calls != calls {
+		t.Errorf("Run with invalid interval should not poll: %v", api.calls)
+	}
+}
+
+func TestWatcherHandler(t *testing.T) {
+	api := &fakeAPI{statuses: map[int]customer.GroupStatus{
+		1: customer.GroupStatusOnline,
+		2: customer.GroupStatusOnlineForQueue,
+	}}
+	now := time.Date(2020, 5, 7, 8, 0, 0, 0, time.UTC)
+	w := availability.NewWatcher(api).WithClock(func() time.Time { return now })
+	handler := w.Handler()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/groups", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("snapshot should not be available before first poll: %v", rec.Code)
+	}
+
+	w.Poll()
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/groups?group_id=2&group_id=3", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("invalid status code: %v", rec.Code)
+	}
+	var body struct {
+		Groups    map[string]string `json:"groups"`
+		UpdatedAt time.Time         `json:"updated_at"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("couldn't unmarshal snapshot: %v", err)
+	}
+	if len(body.Groups) != 1 || body.Groups["2"] != "online_for_queue" || !body.UpdatedAt.Equal(now) {
+		t.Errorf("invalid snapshot: %s", rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("POST", "/groups", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("invalid status code: %v", rec.Code)
+	}
+}
@@ -0,0 +1,211 @@
+package customer
+
+import (
+	"fmt"
+	"net/mail"
+	"strings"
+
+	"github.com/livechat/lc-sdk-go/v2/objects"
+)
+
+// FormFieldKind represents type of a FormField.
+type FormFieldKind string
+
+// Supported values of FormFieldKind.
+const (
+	// FormFieldHeader is an informational text, it cannot be answered.
+	FormFieldHeader FormFieldKind = "header"
+	// FormFieldName is answered with customer's name.
+	FormFieldName FormFieldKind = "name"
+	// FormFieldEmail is answered with customer's email address.
+	FormFieldEmail FormFieldKind = "email"
+	// FormFieldQuestion is answered with free text.
+	FormFieldQuestion FormFieldKind = "question"
+	// FormFieldRadio is answered with single option.
+	FormFieldRadio FormFieldKind = "radio"
+	// FormFieldSelect is answered with single option.
+	FormFieldSelect FormFieldKind = "select"
+	// FormFieldCheckbox is answered with any number of options.
+	FormFieldCheckbox FormFieldKind = "checkbox"
+	// FormFieldGroupChooser is answered with single option representing a group the chat is started in.
+	FormFieldGroupChooser FormFieldKind = "group_chooser"
+)
+
+// Kind returns type of field as FormFieldKind.
+func (f FormField) Kind() FormFieldKind {
+	return FormFieldKind(f.Type)
+}
+
+// IsChoice checks if field is answered with its options.
+func (f FormField) IsChoice() bool {
+	switch f.Kind() {
+	case FormFieldRadio, FormFieldSelect, FormFieldCheckbox, FormFieldGroupChooser:
+		return true
+	}
+	return false
+}
+
+// Option returns option with given ID.
+func (f FormField) Option(id string) (FormFieldOption, bool) {
+	for _, o := range f.Options {
+		if o.ID == id {
+			return o, true
+		}
+	}
+	return FormFieldOption{}, false
+}
+
+// FormAnswers maps IDs of form fields to their answers.
+//
+// Text fields are answered with single value, choice fields with IDs of selected options.
+type FormAnswers map[string][]string
+
+// Set sets answer of given field.
+func (fa FormAnswers) Set(fieldID string, values ...string) FormAnswers {
+	fa[fieldID] = values
+	return fa
+}
+
+// Validate checks if answers refer to existing fields, all required fields are answered and answers match
+// field kinds and options.
+func (f *Form) Validate(answers FormAnswers) error {
+	fields := make(map[string]FormField, len(f.Fields))
+	for _, field := range f.Fields {
+		fields[field.ID] = field
+	}
+	for id := range answers {
+		if _, exists := fields[id]; !exists {
+			return fmt.Errorf("form %s has no field %s", f.ID, id)
+		}
+	}
+
+	for _, field := range f.Fields {
+		values := nonEmpty(answers[field.ID])
+		if len(values) == 0 {
+			if field.Required {
+				return fmt.Errorf("field %q is required", field.Label)
+			}
+			continue
+		}
+		if err := validateAnswer(field, values); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateAnswer(field FormField, values []string) error {
+	switch field.Kind() {
+	case FormFieldHeader:
+		return fmt.Errorf("field %q cannot be answered", field.Label)
+	case FormFieldName, FormFieldEmail, FormFieldQuestion:
+		if len(values) > 1 {
+			return fmt.Errorf("field %q accepts single answer", field.Label)
+		}
+		if field.Kind() == FormFieldEmail {
+			if _, err := mail.ParseAddress(values[0]); err != nil {
+				return fmt.Errorf("field %q has invalid email address: %q", field.Label, values[0])
+			}
+		}
+	case FormFieldRadio, FormFieldSelect, FormFieldGroupChooser, FormFieldCheckbox:
+		if len(values) > 1 && field.Kind() != FormFieldCheckbox {
+			return fmt.Errorf("field %q accepts single option", field.Label)
+		}
+		seen := make(map[string]bool)
+		for _, v := range values {
+			if _, exists := field.Option(v); !exists {
+				return fmt.Errorf("field %q has no option %s", field.Label, v)
+			}
+			if seen[v] {
+				return fmt.Errorf("field %q option %s selected twice", field.Label, v)
+			}
+			seen[v] = true
+		}
+	default:
+		return fmt.Errorf("field %q has unsupported type: %s", field.Label, field.Type)
+	}
+	return nil
+}
+
+// Fill validates answers and builds filled_form event with answered fields in order of the form.
+// Choice fields are filled with labels of selected options, joined with ", ". The value is ambiguous if any
+// label contains a comma, so selected options should be taken from answers rather than parsed from the event.
+func (f *Form) Fill(answers FormAnswers) (*objects.FilledForm, error) {
+	if err := f.Validate(answers); err != nil {
+		return nil, err
+	}
+	filled := objects.NewFilledForm(f.ID)
+	for _, field := range f.Fields {
+		values := nonEmpty(answers[field.ID])
+		if len(values) == 0 {
+			continue
+		}
+		if field.IsChoice() {
+			for i, id := range values {
+				o, _ := field.Option(id)
+				values[i] = o.Label
+			}
+		}
+		filled.Fields = append(filled.Fields, objects.FilledFormField{
+			ID:    field.ID,
+			Label: field.Label,
+			Type:  field.Type,
+			Value: strings.Join(values, ", "),
+		})
+	}
+	if len(filled.Fields) == 0 {
+		return nil, fmt.Errorf("form %s has no answers", f.ID)
+	}
+	return filled, nil
+}
+
+// GroupID returns ID of the group chosen in group_chooser field of the form.
+func (f *Form) GroupID(answers FormAnswers) (int, bool) {
+	for _, field := range f.Fields {
+		if field.Kind() != FormFieldGroupChooser {
+			continue
+		}
+		if values := nonEmpty(answers[field.ID]); len(values) == 1 {
+			if o, exists := field.Option(values[0]); exists {
+				return o.GroupID(), true
+			}
+		}
+	}
+	return 0, false
+}
+
+// StartChatWithForm starts new chat like StartChat, with filled_form event built from form and answers as
+// the first event of initial thread. Chat is started in group chosen in the form unless initialChat
+// specifies access.
+func (a *API) StartChatWithForm(form *Form, answers FormAnswers, initialChat *objects.InitialChat, continuous bool) (chatID, threadID string, eventIDs []string, err error) {
+	filled, err := form.Fill(answers)
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	chat := objects.InitialChat{}
+	if initialChat != nil {
+		chat = *initialChat
+	}
+	thread := objects.InitialThread{}
+	if chat.Thread != nil {
+		thread = *chat.Thread
+	}
+	thread.Events = append([]interface{}{filled}, thread.Events...)
+	chat.Thread = &thread
+	if groupID, chosen := form.GroupID(answers); chosen && chat.Access == nil {
+		chat.Access = &objects.Access{GroupIDs: []int{groupID}}
+	}
+
+	return a.StartChat(&chat, continuous)
+}
+
+func nonEmpty(values []string) []string {
+	var result []string
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			result = append(result, v)
+		}
+	}
+	return result
+}
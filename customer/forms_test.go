@@ -0,0 +1,132 @@
+package customer_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/livechat/lc-sdk-go/v2/customer"
+	"github.com/livechat/lc-sdk-go/v2/objects"
+)
+
+func newTestForm() *customer.Form {
+	return &customer.Form{
+		ID: "156630109416307809",
+		Fields: []customer.FormField{
+			{ID: "header", Type: "header", Label: "Welcome!"},
+			{ID: "name", Type: "name", Label: "Name:"},
+			{ID: "email", Type: "email", Label: "E-mail:", Required: true},
+			{ID: "topics", Type: "checkbox", Label: "Topics:", Options: []customer.FormFieldOption{
+				{ID: "0", Label: "Billing"}, {ID: "1", Label: "Shipping"},
+			}},
+			{ID: "department", Type: "group_chooser", Label: "Department:", Required: true, Options: []customer.FormFieldOption{
+				{ID: "0", Type: 1, Label: "Marketing"}, {ID: "1", Type: 2, Label: "Sales"},
+			}},
+		},
+	}
+}
+
+func TestFormValidate(t *testing.T) {
+	form := newTestForm()
+	valid := customer.FormAnswers{}.Set("email", "jane@example.com").Set("department", "1").Set("topics", "0", "1")
+	if err := form.Validate(valid); err != nil {
+		t.Errorf("answers should be valid: %v", err)
+	}
+
+	for name, answers := range map[string]customer.FormAnswers{
+		"missing required": customer.FormAnswers{}.Set("email", "jane@example.com"),
+		"invalid email":    customer.FormAnswers{}.Set("email", "jane").Set("department", "1"),
+		"unknown option":   customer.FormAnswers{}.Set("email", "jane@example.com").Set("department", "5"),
+		"multiple options": customer.FormAnswers{}.Set("email", "jane@example.com").Set("department", "0", "1"),
+		"unknown field":    customer.FormAnswers{}.Set("email", "jane@example.com").Set("department", "1").Set("phone", "123"),
+		"answered header":  customer.FormAnswers{}.Set("email", "jane@example.com").Set("department", "1").Set("header", "hi"),
+	} {
+		if err := form.Validate(answers); err == nil {
+			t.Errorf("%s: answers should be invalid", name)
+		}
+	}
+}
+
+func TestFormFill(t *testing.T) {
+	form := newTestForm()
+	answers := customer.FormAnswers{}.Set("email", "jane@example.com").Set("department", "1").Set("topics", "0", "1").Set("name", " ")
+
+	filled, err := form.Fill(answers)
+	if err != nil {
+		t.Fatalf("Fill failed: %v", err)
+	}
+	if err := filled.Validate(); err != nil {
+		t.Errorf("filled form should be valid: %v", err)
+	}
+	expected := []objects.FilledFormField{
+		{ID: "email", Label: "E-mail:", Type: "email", Value: "jane@example.com"},
+		{ID: "topics", Label: "Topics:", Type: "checkbox", Value: "Billing, Shipping"},
+		{ID: "department", Label: "Department:", Type: "group_chooser", Value: "Sales"},
+	}
+	if filled.FormID != form.ID || len(filled.Fields) != len(expected) {
+		t.Fatalf("invalid filled form: %+v", filled)
+	}
+	for i := range expected {
+		if filled.Fields[i] != expected[i] {
+			t.Errorf("invalid field %d: %+v", i, filled.Fields[i])
+		}
+	}
+	if groupID, ok := form.GroupID(answers); !ok || groupID != 2 {
+		t.Errorf("invalid group: %v, %v", groupID, ok)
+	}
+}
+
+func TestStartChatWithFormShouldSendFilledForm(t *testing.T) {
+	var sent struct {
+		Chat struct {
+			Access struct {
+				GroupIDs []int `json:"group_ids"`
+			} `json:"access"`
+			Thread struct {
+				Events []objects.Event `json:"events"`
+			} `json:"thread"`
+		} `json:"chat"`
+	}
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		body, _ := ioutil.ReadAll(req.Body)
+		if err := json.Unmarshal(body, &sent); err != nil {
+			t.Errorf("couldn't unmarshal request: %v", err)
+		}
+		return &http.Response{
+			StatusCode: 200,
+			Body:       ioutil.NopCloser(bytes.NewBufferString(`{"chat_id": "PJ0MRSHTDG", "thread_id": "PGDGHT5G"}`)),
+			Header:     make(http.Header),
+		}
+	})
+
+	api, err := customer.NewAPI(stubTokenGetter, client, "client_id")
+	if err != nil {
+		t.Errorf("API creation failed")
+	}
+
+	initialChat := &objects.InitialChat{Thread: &objects.InitialThread{Events: []interface{}{objects.NewMessage("Hello")}}}
+	answers := customer.FormAnswers{}.Set("email", "jane@example.com").Set("department", "0")
+	chatID, _, _, rErr := api.StartChatWithForm(newTestForm(), answers, initialChat, false)
+	if rErr != nil {
+		t.Fatalf("StartChatWithForm failed: %v", rErr)
+	}
+
+	if chatID != "PJ0MRSHTDG" {
+		t.Errorf("invalid chat ID: %v", chatID)
+	}
+	events := sent.Chat.Thread.Events
+	if len(events) != 2 || events[0].Type != "filled_form" || events[1].Type != "message" {
+		t.Fatalf("invalid events: %+v", events)
+	}
+	if f := events[0].FilledForm(); f == nil || len(f.Fields) != 2 || f.Fields[1].Value != "Marketing" {
+		t.Errorf("invalid filled form: %+v", f)
+	}
+	if len(sent.Chat.Access.GroupIDs) != 1 || sent.Chat.Access.GroupIDs[0] != 1 {
+		t.Errorf("chat should be started in chosen group: %v", sent.Chat.Access)
+	}
+	if len(initialChat.Thread.Events) != 1 {
+		t.Errorf("initial chat should not be modified")
+	}
+}
@@ -122,14 +122,14 @@ type deleteEventPropertiesRequest struct {
 }
 
 type updateCustomerRequest struct {
-	Name          string              `json:"name,omitempty"`
-	Email         string              `json:"email,omitempty"`
-	Avatar        string              `json:"avatar,omitempty"`
-	SessionFields []map[string]string `json:"session_fields,omitempty"`
+	Name          string                `json:"name,omitempty"`
+	Email         string                `json:"email,omitempty"`
+	Avatar        string                `json:"avatar,omitempty"`
+	SessionFields objects.SessionFields `json:"session_fields,omitempty"`
 }
 
 type setCustomerSessionFieldsRequest struct {
-	SessionFields []map[string]string `json:"session_fields"`
+	SessionFields objects.SessionFields `json:"session_fields"`
 }
 
 type listGroupStatusesRequest struct {
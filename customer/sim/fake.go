@@ -0,0 +1,142 @@
+package sim
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"path"
+	"sync"
+	"time"
+)
+
+// FakeEndpoint is an http.Handler imitating Customer Chat API actions used by simulated customers.
+//
+// It keeps track of started chats and rejects events sent to unknown or inactive chats, similarly to
+// the real API. Latency and random failures can be injected to test behaviour of the system under load.
+type FakeEndpoint struct {
+	latency     time.Duration
+	failureRate float64
+
+	mu     sync.Mutex
+	rand   *rand.Rand
+	chats  map[string]bool
+	nextID int
+	calls  map[string]int
+}
+
+// NewFakeEndpoint creates FakeEndpoint responding immediately and never failing.
+func NewFakeEndpoint() *FakeEndpoint {
+	return &FakeEndpoint{
+		rand:  rand.New(rand.NewSource(time.Now().UnixNano())),
+		chats: make(map[string]bool),
+		calls: make(map[string]int),
+	}
+}
+
+// WithLatency sets time FakeEndpoint waits before responding.
+func (f *FakeEndpoint) WithLatency(d time.Duration) *FakeEndpoint {
+	f.latency = d
+	return f
+}
+
+// WithFailureRate sets fraction (0-1) of requests randomly failed with internal error.
+func (f *FakeEndpoint) WithFailureRate(rate float64, seed int64) *FakeEndpoint {
+	f.failureRate = rate
+	f.rand = rand.New(rand.NewSource(seed))
+	return f
+}
+
+// Calls returns number of received requests of given action.
+func (f *FakeEndpoint) Calls(action string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls[action]
+}
+
+// ActiveChats returns number of started chats that were not deactivated.
+func (f *FakeEndpoint) ActiveChats() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	n := 0
+	for _, active := range f.chats {
+		if active {
+			n++
+		}
+	}
+	return n
+}
+
+type fakeRequest struct {
+	ChatID string `json:"chat_id"`
+}
+
+// ServeHTTP handles single Customer Chat API request.
+func (f *FakeEndpoint) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if f.latency > 0 {
+		time.Sleep(f.latency)
+	}
+	if r.Header.Get("Authorization") == "" {
+		writeFakeError(w, http.StatusUnauthorized, "authentication", "missing authorization")
+		return
+	}
+	var req fakeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeFakeError(w, http.StatusBadRequest, "validation", fmt.Sprintf("invalid request body: %v", err))
+		return
+	}
+
+	action := path.Base(r.URL.Path)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls[action]++
+	if f.failureRate > 0 && f.rand.Float64() < f.failureRate {
+		writeFakeError(w, http.StatusInternalServerError, "internal", "injected failure")
+		return
+	}
+
+	switch action {
+	case "start_chat":
+		f.nextID++
+		chatID := fmt.Sprintf("FAKECHAT%d", f.nextID)
+		f.chats[chatID] = true
+		writeFakeResponse(w, map[string]interface{}{
+			"chat_id":   chatID,
+			"thread_id": fmt.Sprintf("FAKETHREAD%d", f.nextID),
+			"event_ids": []string{},
+		})
+	case "send_event", "send_sneak_peek", "mark_events_as_seen", "deactivate_chat":
+		active, exists := f.chats[req.ChatID]
+		if !exists {
+			writeFakeError(w, http.StatusNotFound, "not_found", fmt.Sprintf("chat %s not found", req.ChatID))
+			return
+		}
+		if !active {
+			writeFakeError(w, http.StatusUnprocessableEntity, "chat_inactive", fmt.Sprintf("chat %s is inactive", req.ChatID))
+			return
+		}
+		switch action {
+		case "send_event":
+			writeFakeResponse(w, map[string]interface{}{"event_id": fmt.Sprintf("%s_%d", req.ChatID, f.calls[action])})
+			return
+		case "deactivate_chat":
+			f.chats[req.ChatID] = false
+		}
+		writeFakeResponse(w, map[string]interface{}{})
+	default:
+		writeFakeError(w, http.StatusNotFound, "not_found", fmt.Sprintf("unsupported action: %s", action))
+	}
+}
+
+func writeFakeResponse(w http.ResponseWriter, payload interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(payload)
+}
+
+func writeFakeError(w http.ResponseWriter, status int, errType, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"error": map[string]string{"type": errType, "message": message},
+	})
+}
@@ -0,0 +1,146 @@
+package sim
+
+import (
+	"fmt"
+	"time"
+)
+
+// StepKind represents type of a persona's script step.
+type StepKind int
+
+// Possible values of StepKind.
+const (
+	// StepStart starts a chat, optionally with an initial message.
+	StepStart StepKind = iota
+	// StepSay sends a message.
+	StepSay
+	// StepType sends a sneak peek, waits a think time and sends a message.
+	StepType
+	// StepSeen marks all events in chat as seen.
+	StepSeen
+	// StepWait waits given time.
+	StepWait
+	// StepLeave deactivates the chat.
+	StepLeave
+)
+
+var stepKindNames = map[StepKind]string{
+	StepStart: "start",
+	StepSay:   "say",
+	StepType:  "type",
+	StepSeen:  "seen",
+	StepWait:  "wait",
+	StepLeave: "leave",
+}
+
+func (k StepKind) String() string {
+	if name, exists := stepKindNames[k]; exists {
+		return name
+	}
+	return fmt.Sprintf("StepKind(%d)", int(k))
+}
+
+// Step represents single action of a persona.
+type Step struct {
+	Kind     StepKind
+	Text     string
+	GroupID  int
+	Duration time.Duration
+}
+
+// Persona represents a scripted customer.
+//
+// Think time, drawn uniformly from [MinThinkTime, MaxThinkTime], passes before every step except
+// StepWait and the first one.
+type Persona struct {
+	Name         string
+	Steps        []Step
+	MinThinkTime time.Duration
+	MaxThinkTime time.Duration
+}
+
+// NewPersona creates Persona with empty script and no think time.
+func NewPersona(name string) *Persona {
+	return &Persona{Name: name}
+}
+
+// WithThinkTime sets range of think time.
+func (p *Persona) WithThinkTime(min, max time.Duration) *Persona {
+	p.MinThinkTime = min
+	p.MaxThinkTime = max
+	return p
+}
+
+// Start adds step starting a chat in given group. Chat is started with initial message if text is not empty.
+func (p *Persona) Start(groupID int, text string) *Persona {
+	p.Steps = append(p.Steps, Step{Kind: StepStart, GroupID: groupID, Text: text})
+	return p
+}
+
+// Say adds step sending a message.
+func (p *Persona) Say(text string) *Persona {
+	p.Steps = append(p.Steps, Step{Kind: StepSay, Text: text})
+	return p
+}
+
+// Type adds step sending a sneak peek followed by a message.
+func (p *Persona) Type(text string) *Persona {
+	p.Steps = append(p.Steps, Step{Kind: StepType, Text: text})
+	return p
+}
+
+// Seen adds step marking events as seen.
+func (p *Persona) Seen() *Persona {
+	p.Steps = append(p.Steps, Step{Kind: StepSeen})
+	return p
+}
+
+// Wait adds step waiting given time.
+func (p *Persona) Wait(d time.Duration) *Persona {
+	p.Steps = append(p.Steps, Step{Kind: StepWait, Duration: d})
+	return p
+}
+
+// Leave adds step deactivating the chat.
+func (p *Persona) Leave() *Persona {
+	p.Steps = append(p.Steps, Step{Kind: StepLeave})
+	return p
+}
+
+// Validate checks if Persona's script is not empty and its steps are executed in proper chat state,
+// ie. messages are sent only in started chats and chats are not started twice.
+func (p *Persona) Validate() error {
+	if len(p.Steps) == 0 {
+		return fmt.Errorf("persona %q has empty script", p.Name)
+	}
+	if p.MinThinkTime < 0 || p.MaxThinkTime < p.MinThinkTime {
+		return fmt.Errorf("persona %q has invalid think time range: %v-%v", p.Name, p.MinThinkTime, p.MaxThinkTime)
+	}
+	active := false
+	for i, s := range p.Steps {
+		switch s.Kind {
+		case StepStart:
+			if active {
+				return fmt.Errorf("persona %q step %d: chat already started", p.Name, i)
+			}
+			active = true
+		case StepSay, StepType, StepSeen, StepLeave:
+			if !active {
+				return fmt.Errorf("persona %q step %d: %v requires started chat", p.Name, i, s.Kind)
+			}
+			if s.Kind == StepLeave {
+				active = false
+			}
+			if (s.Kind == StepSay || s.Kind == StepType) && s.Text == "" {
+				return fmt.Errorf("persona %q step %d: message text cannot be empty", p.Name, i)
+			}
+		case StepWait:
+			if s.Duration < 0 {
+				return fmt.Errorf("persona %q step %d: negative wait", p.Name, i)
+			}
+		default:
+			return fmt.Errorf("persona %q step %d: unknown step kind: %v", p.Name, i, s.Kind)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,232 @@
+// Package sim simulates customers chatting through Customer Chat API.
+//
+// Simulated customers follow scripts of Personas, ie.
+//
+//	shopper := sim.NewPersona("shopper").
+//		WithThinkTime(time.Second, 5*time.Second).
+//		Start(0, "Hi, where is my order?").
+//		Type("It's #1234").
+//		Seen().
+//		Leave()
+//
+//	stats, err := sim.NewSimulator(newAPI).WithConcurrency(50).Run(ctx, 1000, shopper)
+//
+// Each simulated customer uses its own API created by APIFactory, so it can be authorized as separate customer.
+// For tests without access to a license, customer.API can be pointed at FakeEndpoint served locally:
+//
+//	server := httptest.NewServer(sim.NewFakeEndpoint())
+//	api.SetCustomHost(server.URL)
+package sim
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/livechat/lc-sdk-go/v2/customer"
+	"github.com/livechat/lc-sdk-go/v2/objects"
+)
+
+// API is a subset of customer.API used by simulated customers.
+type API interface {
+	StartChat(initialChat *objects.InitialChat, continuous bool) (chatID, threadID string, eventIDs []string, err error)
+	SendMessage(chatID, text string, recipients customer.Recipients) (string, error)
+	SendSneakPeek(chatID, text string) error
+	MarkEventsAsSeen(chatID string, seenUpTo time.Time) error
+	DeactivateChat(chatID string) error
+}
+
+// APIFactory creates API for simulated customer with given index.
+type APIFactory func(customer int) (API, error)
+
+// DefaultConcurrency is the default number of customers simulated at the same time.
+const DefaultConcurrency = 10
+
+// Simulator runs simulated customers.
+type Simulator struct {
+	newAPI      APIFactory
+	concurrency int
+
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+// NewSimulator creates Simulator running DefaultConcurrency customers at the same time.
+func NewSimulator(newAPI APIFactory) *Simulator {
+	return &Simulator{
+		newAPI:      newAPI,
+		concurrency: DefaultConcurrency,
+		rand:        rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// WithConcurrency sets maximum number of customers simulated at the same time.
+func (s *Simulator) WithConcurrency(n int) *Simulator {
+	s.concurrency = n
+	return s
+}
+
+// WithSeed sets seed of generator used to draw think times, making them reproducible.
+func (s *Simulator) WithSeed(seed int64) *Simulator {
+	s.rand = rand.New(rand.NewSource(seed))
+	return s
+}
+
+// Run simulates given number of customers, assigning them personas in round-robin fashion.
+//
+// Customer's script is aborted on first failed call (its chat is deactivated if it was started) or when ctx
+// is done. Failures are reported in returned Stats, error is returned only for invalid arguments.
+func (s *Simulator) Run(ctx context.Context, customers int, personas ...*Persona) (*Stats, error) {
+	if len(personas) == 0 {
+		return nil, fmt.Errorf("at least one persona is required")
+	}
+	for _, p := range personas {
+		if err := p.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	concurrency := s.concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	stats := newStats()
+	start := time.Now()
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i := 0; i < customers; i++ {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			stats.finish(s.simulate(ctx, i, personas[i%len(personas)], stats))
+		}(i)
+	}
+	wg.Wait()
+	stats.Duration = time.Since(start)
+	for _, as := range stats.Actions {
+		sortDurations(as.Latencies)
+	}
+	return stats, nil
+}
+
+type session struct {
+	api    API
+	chatID string
+	stats  *Stats
+}
+
+func (s *session) call(action string, fn func() error) error {
+	start := time.Now()
+	err := fn()
+	s.stats.record(action, time.Since(start), err)
+	return err
+}
+
+func (s *Simulator) simulate(ctx context.Context, i int, p *Persona, stats *Stats) bool {
+	api, err := s.newAPI(i)
+	if err != nil {
+		stats.record("create_api", 0, err)
+		return false
+	}
+	sess := &session{api: api, stats: stats}
+
+	for n, step := range p.Steps {
+		if n > 0 && step.Kind != StepWait {
+			if err := sleep(ctx, s.thinkTime(p)); err != nil {
+				break
+			}
+		}
+		if err := s.execute(ctx, sess, p, step); err != nil {
+			break
+		}
+		if n == len(p.Steps)-1 {
+			return true
+		}
+	}
+
+	if sess.chatID != "" {
+		sess.call("deactivate_chat", func() error {
+			return sess.api.DeactivateChat(sess.chatID)
+		})
+	}
+	return false
+}
+
+func (s *Simulator) execute(ctx context.Context, sess *session, p *Persona, step Step) error {
+	switch step.Kind {
+	case StepStart:
+		chat := &objects.InitialChat{Access: &objects.Access{GroupIDs: []int{step.GroupID}}}
+		if step.Text != "" {
+			chat.Thread = &objects.InitialThread{Events: []interface{}{objects.NewMessage(step.Text)}}
+		}
+		return sess.call("start_chat", func() error {
+			chatID, _, _, err := sess.api.StartChat(chat, false)
+			sess.chatID = chatID
+			return err
+		})
+	case StepType:
+		err := sess.call("send_sneak_peek", func() error {
+			return sess.api.SendSneakPeek(sess.chatID, step.Text)
+		})
+		if err != nil {
+			return err
+		}
+		if err := sleep(ctx, s.thinkTime(p)); err != nil {
+			return err
+		}
+		fallthrough
+	case StepSay:
+		return sess.call("send_event", func() error {
+			_, err := sess.api.SendMessage(sess.chatID, step.Text, customer.All)
+			return err
+		})
+	case StepSeen:
+		return sess.call("mark_events_as_seen", func() error {
+			return sess.api.MarkEventsAsSeen(sess.chatID, time.Now())
+		})
+	case StepWait:
+		return sleep(ctx, step.Duration)
+	case StepLeave:
+		err := sess.call("deactivate_chat", func() error {
+			return sess.api.DeactivateChat(sess.chatID)
+		})
+		sess.chatID = ""
+		return err
+	}
+	return fmt.Errorf("unknown step kind: %v", step.Kind)
+}
+
+func (s *Simulator) thinkTime(p *Persona) time.Duration {
+	if p.MaxThinkTime <= p.MinThinkTime {
+		return p.MinThinkTime
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return p.MinThinkTime + time.Duration(s.rand.Int63n(int64(p.MaxThinkTime-p.MinThinkTime)+1))
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,188 @@
+package sim_test
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/livechat/lc-sdk-go/v2/authorization"
+	"github.com/livechat/lc-sdk-go/v2/customer"
+	"github.com/livechat/lc-sdk-go/v2/customer/sim"
+	"github.com/livechat/lc-sdk-go/v2/objects"
+)
+
+var _ sim.API = &customer.API{}
+
+func stubTokenGetter() *authorization.Token {
+	licenseID := 12345
+	return &authorization.Token{
+		LicenseID:   &licenseID,
+		AccessToken: "access_token",
+		Region:      "region",
+		Type:        authorization.BearerToken,
+	}
+}
+
+func newFakeAPIFactory(url string) sim.APIFactory {
+	return func(int) (sim.API, error) {
+		api, err := customer.NewAPI(stubTokenGetter, nil, "client_id")
+		if err != nil {
+			return nil, err
+		}
+		api.SetCustomHost(url)
+		return api, nil
+	}
+}
+
+func TestSimulatorRunsPersonasAgainstFakeEndpoint(t *testing.T) {
+	endpoint := sim.NewFakeEndpoint()
+	server := httptest.NewServer(endpoint)
+	defer server.Close()
+
+	shopper := sim.NewPersona("shopper").
+		WithThinkTime(0, time.Millisecond).
+		Start(0, "Hi").
+		Type("Where is my order?").
+		Seen().
+		Leave()
+	lurker := sim.NewPersona("lurker").Start(1, "").Wait(time.Millisecond).Leave()
+
+	stats, err := sim.NewSimulator(newFakeAPIFactory(server.URL)).
+		WithConcurrency(3).
+		WithSeed(1).
+		Run(context.Background(), 10, shopper, lurker)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if stats.Customers != 10 || stats.Completed != 10 || stats.SuccessRate() != 1 {
+		t.Errorf("invalid stats: %v, errors: %v", stats, stats.Errors)
+	}
+	if endpoint.Calls("start_chat") != 10 || endpoint.Calls("send_event") != 5 || endpoint.Calls("send_sneak_peek") != 5 {
+		t.Errorf("invalid number of calls: %v", stats)
+	}
+	if endpoint.ActiveChats() != 0 {
+		t.Errorf("all chats should be deactivated")
+	}
+	if as := stats.Actions["start_chat"]; as.Calls != 10 || as.Percentile(100) < as.Percentile(50) {
+		t.Errorf("invalid start_chat stats: %+v", as)
+	}
+	if !strings.Contains(stats.String(), "10/10 customers completed") {
+		t.Errorf("invalid summary: %v", stats)
+	}
+}
+
+type failingAPI struct {
+	mu          sync.Mutex
+	deactivated int
+	concurrent  int
+	maxObserved int
+}
+
+func (f *failingAPI) StartChat(*objects.InitialChat, bool) (string, string, []string, error) {
+	f.mu.Lock()
+	f.concurrent++
+	if f.concurrent > f.maxObserved {
+		f.maxObserved = f.concurrent
+	}
+	f.mu.Unlock()
+	time.Sleep(time.Millisecond)
+	f.mu.Lock()
+	f.concurrent--
+	f.mu.Unlock()
+	return "PJ0MRSHTDG", "K600PKZON8", nil, nil
+}
+
+func (f *failingAPI) SendMessage(string, string, customer.Recipients) (string, error) {
+	return "", errors.New("boom")
+}
+
+func (f *failingAPI) SendSneakPeek(string, string) error {
+	return nil
+}
+
+func (f *failingAPI) MarkEventsAsSeen(string, time.Time) error {
+	return nil
+}
+
+func (f *failingAPI) DeactivateChat(string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.deactivated++
+	return nil
+}
+
+func TestSimulatorAbortsScriptOnFailure(t *testing.T) {
+	api := &failingAPI{}
+	persona := sim.NewPersona("talker").Start(0, "").Say("Hello").Seen().Leave()
+
+	stats, err := sim.NewSimulator(func(int) (sim.API, error) { return api, nil }).
+		WithConcurrency(2).
+		Run(context.Background(), 6, persona)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if stats.Completed != 0 || stats.Errors["boom"] != 6 {
+		t.Errorf("invalid stats: %v, errors: %v", stats, stats.Errors)
+	}
+	if _, exists := stats.Actions["mark_events_as_seen"]; exists {
+		t.Errorf("script should be aborted after failure")
+	}
+	if api.deactivated != 6 {
+		t.Errorf("started chats should be deactivated after failure: %v", api.deactivated)
+	}
+	if api.maxObserved > 2 {
+		t.Errorf("concurrency limit exceeded: %v", api.maxObserved)
+	}
+}
+
+func TestSimulatorStopsOnCancelledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	persona := sim.NewPersona("waiter").Start(0, "").Wait(time.Hour).Leave()
+	stats, err := sim.NewSimulator(func(int) (sim.API, error) { return &failingAPI{}, nil }).Run(ctx, 5, persona)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if stats.Completed != 0 {
+		t.Errorf("no customer should complete: %v", stats)
+	}
+}
+
+func TestPersonaValidate(t *testing.T) {
+	for _, p := range []*sim.Persona{
+		sim.NewPersona("empty"),
+		sim.NewPersona("not started").Say("Hi"),
+		sim.NewPersona("started twice").Start(0, "").Start(0, ""),
+		sim.NewPersona("empty message").Start(0, "").Say(""),
+		sim.NewPersona("after leave").Start(0, "").Leave().Seen(),
+		sim.NewPersona("invalid think time").WithThinkTime(time.Second, 0).Start(0, ""),
+	} {
+		if err := p.Validate(); err == nil {
+			t.Errorf("persona %q should be invalid", p.Name)
+		}
+	}
+	if _, err := sim.NewSimulator(nil).Run(context.Background(), 1); err == nil {
+		t.Errorf("Run without personas should fail")
+	}
+}
+
+func TestFakeEndpointInjectsFailures(t *testing.T) {
+	endpoint := sim.NewFakeEndpoint().WithFailureRate(1, 1)
+	server := httptest.NewServer(endpoint)
+	defer server.Close()
+
+	api, _ := newFakeAPIFactory(server.URL)(0)
+	if _, _, _, err := api.StartChat(&objects.InitialChat{}, false); err == nil || !strings.Contains(err.Error(), "injected failure") {
+		t.Errorf("StartChat should fail: %v", err)
+	}
+	if err := api.DeactivateChat("UNKNOWN"); err == nil {
+		t.Errorf("DeactivateChat should fail")
+	}
+}
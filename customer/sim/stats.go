@@ -0,0 +1,130 @@
+package sim
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ActionStats aggregates calls of single Customer Chat API action.
+type ActionStats struct {
+	Calls    int
+	Failures int
+	// Latencies are sorted ascending.
+	Latencies []time.Duration
+}
+
+// Mean returns average latency of action calls.
+func (as *ActionStats) Mean() time.Duration {
+	if len(as.Latencies) == 0 {
+		return 0
+	}
+	var sum time.Duration
+	for _, l := range as.Latencies {
+		sum += l
+	}
+	return sum / time.Duration(len(as.Latencies))
+}
+
+// Percentile returns latency below which given percent (0-100) of calls fall, using nearest-rank method.
+func (as *ActionStats) Percentile(p float64) time.Duration {
+	if len(as.Latencies) == 0 {
+		return 0
+	}
+	rank := int(p / 100 * float64(len(as.Latencies)))
+	if float64(rank) < p/100*float64(len(as.Latencies)) {
+		rank++
+	}
+	if rank < 1 {
+		rank = 1
+	}
+	if rank > len(as.Latencies) {
+		rank = len(as.Latencies)
+	}
+	return as.Latencies[rank-1]
+}
+
+// Stats summarizes a simulation run.
+type Stats struct {
+	// Customers is the number of simulated customers.
+	Customers int
+	// Completed is the number of customers that finished their scripts without errors.
+	Completed int
+	Duration  time.Duration
+	// Actions maps Customer Chat API action names (ie. start_chat) to their statistics.
+	Actions map[string]*ActionStats
+	// Errors maps error messages to number of their occurrences.
+	Errors map[string]int
+
+	mu sync.Mutex
+}
+
+func newStats() *Stats {
+	return &Stats{
+		Actions: make(map[string]*ActionStats),
+		Errors:  make(map[string]int),
+	}
+}
+
+func (s *Stats) record(action string, latency time.Duration, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	as, exists := s.Actions[action]
+	if !exists {
+		as = &ActionStats{}
+		s.Actions[action] = as
+	}
+	as.Calls++
+	as.Latencies = append(as.Latencies, latency)
+	if err != nil {
+		as.Failures++
+		s.Errors[err.Error()]++
+	}
+}
+
+func (s *Stats) finish(completed bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Customers++
+	if completed {
+		s.Completed++
+	}
+}
+
+// SuccessRate returns fraction of successful calls of all actions.
+func (s *Stats) SuccessRate() float64 {
+	calls, failures := 0, 0
+	for _, as := range s.Actions {
+		calls += as.Calls
+		failures += as.Failures
+	}
+	if calls == 0 {
+		return 0
+	}
+	return float64(calls-failures) / float64(calls)
+}
+
+// String returns human readable summary of Stats.
+func (s *Stats) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d/%d customers completed in %v, %.1f%% calls succeeded\n",
+		s.Completed, s.Customers, s.Duration, 100*s.SuccessRate())
+
+	actions := make([]string, 0, len(s.Actions))
+	for action := range s.Actions {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+	for _, action := range actions {
+		as := s.Actions[action]
+		fmt.Fprintf(&b, "%s: %d calls, %d failed, mean %v, p50 %v, p95 %v, p99 %v\n",
+			action, as.Calls, as.Failures, as.Mean(), as.Percentile(50), as.Percentile(95), as.Percentile(99))
+	}
+	return b.String()
+}
+
+func sortDurations(d []time.Duration) {
+	sort.Slice(d, func(i, j int) bool { return d[i] < d[j] })
+}
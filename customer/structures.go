@@ -2,18 +2,32 @@ package customer
 
 // Form struct describes schema of custom form (e-mail, prechat or postchat survey).
 type Form struct {
-	ID     string `json:"id"`
-	Fields []struct {
-		ID       string `json:"id"`
-		Type     string `json:"type"`
-		Label    string `json:"label"`
-		Required bool   `json:"required"`
-		Options  []struct {
-			ID    string `json:"id"`
-			Type  int    `json:"group_id"`
-			Label string `json:"label"`
-		} `json:"options"`
-	} `json:"fields"`
+	ID     string      `json:"id"`
+	Fields []FormField `json:"fields"`
+}
+
+// FormField describes single field of a Form. Type is one of FormFieldKind values.
+type FormField struct {
+	ID       string            `json:"id"`
+	Type     string            `json:"type"`
+	Label    string            `json:"label"`
+	Required bool              `json:"required"`
+	Options  []FormFieldOption `json:"options"`
+}
+
+// FormFieldOption describes single option of choice FormField.
+type FormFieldOption struct {
+	ID string `json:"id"`
+	// Type holds ID of the group represented by option of group_chooser field.
+	//
+	// Deprecated: use GroupID method instead.
+	Type  int    `json:"group_id"`
+	Label string `json:"label"`
+}
+
+// GroupID returns ID of the group represented by option of group_chooser field.
+func (o FormFieldOption) GroupID() int {
+	return o.Type
 }
 
 // PredictedAgent is an agent returned by GetPredictedAgent method.
@@ -1,5 +1,7 @@
 package customer
 
+import "fmt"
+
 // GroupStatus represents status of groups.
 type GroupStatus int
 
@@ -12,6 +14,20 @@ const (
 	GroupStatusOnlineForQueue
 )
 
+var groupStatusNames = map[GroupStatus]string{
+	GroupStatusUnknown:        "unknown",
+	GroupStatusOnline:         "online",
+	GroupStatusOffline:        "offline",
+	GroupStatusOnlineForQueue: "online_for_queue",
+}
+
+func (s GroupStatus) String() string {
+	if name, exists := groupStatusNames[s]; exists {
+		return name
+	}
+	return fmt.Sprintf("GroupStatus(%d)", int(s))
+}
+
 // FormType represents type of form templates.
 type FormType string
 
@@ -0,0 +1,21 @@
+package customer_test
+
+import (
+	"testing"
+
+	"github.com/livechat/lc-sdk-go/v2/customer"
+)
+
+func TestGroupStatusString(t *testing.T) {
+	for status, name := range map[customer.GroupStatus]string{
+		customer.GroupStatusUnknown:        "unknown",
+		customer.GroupStatusOnline:         "online",
+		customer.GroupStatusOffline:        "offline",
+		customer.GroupStatusOnlineForQueue: "online_for_queue",
+		customer.GroupStatus(42):           "GroupStatus(42)",
+	} {
+		if s := status.String(); s != name {
+			t.Errorf("invalid name of %d: %v", int(status), s)
+		}
+	}
+}
@@ -43,13 +43,7 @@ func (h *IncomingEventHandler) Handle(wh *webhooks.Webhook) error {
 		return errors.New("agent-api initilization failed")
 	}
 
-	msg := &objects.Message{
-		Event: objects.Event{
-			Type:       "message",
-			Recipients: "all",
-		},
-		Text: "You said: " + payload.Event.Message().Text,
-	}
+	msg := objects.NewMessage("You said: " + payload.Event.Message().Text)
 	api.SendEvent(payload.ChatID, msg, true)
 
 	return nil
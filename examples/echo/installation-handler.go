@@ -55,14 +55,15 @@ func (h *InstallationHandler) Handle(w http.ResponseWriter, r *http.Request) {
 		Filters:     &configuration.WebhookFilters{AuthorType: "customer"},
 	}
 
-	whID, err := api.RegisterWebhook(wh)
+	// SyncWebhooks replaces webhooks left by previous installations or deployments instead of adding duplicates.
+	whIDs, err := api.SyncWebhooks(t.ClientID, []*configuration.Webhook{wh})
 	if err != nil {
 		fmt.Println("Error when handling installation: webhook registration failed")
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
 
-	h.tr.Set(whID, t)
+	h.tr.Set(whIDs[wh.Action], t)
 
 	w.WriteHeader(http.StatusOK)
 }
@@ -0,0 +1,162 @@
+package objects
+
+// Possible values of Event's Recipients.
+const (
+	RecipientsAll    = "all"
+	RecipientsAgents = "agents"
+)
+
+// NewMessage creates Message event with given text, visible to all chat users.
+func NewMessage(text string) *Message {
+	return &Message{
+		Event: Event{
+			Type:       "message",
+			Recipients: RecipientsAll,
+		},
+		Text: text,
+	}
+}
+
+// ToAgentsOnly makes Message visible only to agents.
+func (m *Message) ToAgentsOnly() *Message {
+	m.Recipients = RecipientsAgents
+	return m
+}
+
+// ToAll makes Message visible to all chat users.
+func (m *Message) ToAll() *Message {
+	m.Recipients = RecipientsAll
+	return m
+}
+
+// WithProperties sets Message's properties.
+func (m *Message) WithProperties(properties Properties) *Message {
+	m.Properties = properties
+	return m
+}
+
+// WithCustomID sets Message's custom ID.
+func (m *Message) WithCustomID(customID string) *Message {
+	m.CustomID = customID
+	return m
+}
+
+// WithPostback attaches rich message postback to Message.
+func (m *Message) WithPostback(postback *Postback) *Message {
+	m.Postback = postback
+	return m
+}
+
+// NewFile creates File event pointing to given URL (eg. returned by UploadFile), visible to all chat users.
+func NewFile(url string) *File {
+	return &File{
+		Event: Event{
+			Type:       "file",
+			Recipients: RecipientsAll,
+		},
+		URL: url,
+	}
+}
+
+// ToAgentsOnly makes File visible only to agents.
+func (f *File) ToAgentsOnly() *File {
+	f.Recipients = RecipientsAgents
+	return f
+}
+
+// ToAll makes File visible to all chat users.
+func (f *File) ToAll() *File {
+	f.Recipients = RecipientsAll
+	return f
+}
+
+// WithProperties sets File's properties.
+func (f *File) WithProperties(properties Properties) *File {
+	f.Properties = properties
+	return f
+}
+
+// WithCustomID sets File's custom ID.
+func (f *File) WithCustomID(customID string) *File {
+	f.CustomID = customID
+	return f
+}
+
+// NewSystemMessage creates SystemMessage event of given system message type with given text,
+// visible to all chat users.
+func NewSystemMessage(text, messageType string) *SystemMessage {
+	return &SystemMessage{
+		Event: Event{
+			Type:       "system_message",
+			Recipients: RecipientsAll,
+		},
+		Type: messageType,
+		Text: text,
+	}
+}
+
+// ToAgentsOnly makes SystemMessage visible only to agents.
+func (sm *SystemMessage) ToAgentsOnly() *SystemMessage {
+	sm.Recipients = RecipientsAgents
+	return sm
+}
+
+// ToAll makes SystemMessage visible to all chat users.
+func (sm *SystemMessage) ToAll() *SystemMessage {
+	sm.Recipients = RecipientsAll
+	return sm
+}
+
+// WithProperties sets SystemMessage's properties.
+func (sm *SystemMessage) WithProperties(properties Properties) *SystemMessage {
+	sm.Properties = properties
+	return sm
+}
+
+// WithCustomID sets SystemMessage's custom ID.
+func (sm *SystemMessage) WithCustomID(customID string) *SystemMessage {
+	sm.CustomID = customID
+	return sm
+}
+
+// WithTextVars sets SystemMessage's text variables.
+func (sm *SystemMessage) WithTextVars(textVars map[string]string) *SystemMessage {
+	sm.TextVars = textVars
+	return sm
+}
+
+// NewRichMessage creates RichMessage event of given template with given elements, visible to all chat users.
+func NewRichMessage(templateID string, elements ...RichMessageElement) *RichMessage {
+	return &RichMessage{
+		Event: Event{
+			Type:       "rich_message",
+			Recipients: RecipientsAll,
+		},
+		TemplateID: templateID,
+		Elements:   elements,
+	}
+}
+
+// ToAgentsOnly makes RichMessage visible only to agents.
+func (rm *RichMessage) ToAgentsOnly() *RichMessage {
+	rm.Recipients = RecipientsAgents
+	return rm
+}
+
+// ToAll makes RichMessage visible to all chat users.
+func (rm *RichMessage) ToAll() *RichMessage {
+	rm.Recipients = RecipientsAll
+	return rm
+}
+
+// WithProperties sets RichMessage's properties.
+func (rm *RichMessage) WithProperties(properties Properties) *RichMessage {
+	rm.Properties = properties
+	return rm
+}
+
+// WithCustomID sets RichMessage's custom ID.
+func (rm *RichMessage) WithCustomID(customID string) *RichMessage {
+	rm.CustomID = customID
+	return rm
+}
@@ -0,0 +1,84 @@
+package objects
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// CustomEvent represents LiveChat custom event with arbitrary JSON content.
+type CustomEvent struct {
+	Event
+	Content json.RawMessage `json:"content,omitempty"`
+}
+
+// NewCustomEvent creates CustomEvent with given content marshaled to JSON, visible to all chat users.
+func NewCustomEvent(content interface{}) (*CustomEvent, error) {
+	raw, err := json.Marshal(content)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't marshal custom event content: %v", err)
+	}
+	return &CustomEvent{
+		Event: Event{
+			Type:       "custom",
+			Recipients: RecipientsAll,
+		},
+		Content: raw,
+	}, nil
+}
+
+// ToAgentsOnly makes CustomEvent visible only to agents.
+func (c *CustomEvent) ToAgentsOnly() *CustomEvent {
+	c.Recipients = RecipientsAgents
+	return c
+}
+
+// ToAll makes CustomEvent visible to all chat users.
+func (c *CustomEvent) ToAll() *CustomEvent {
+	c.Recipients = RecipientsAll
+	return c
+}
+
+// WithProperties sets CustomEvent's properties.
+func (c *CustomEvent) WithProperties(properties Properties) *CustomEvent {
+	c.Properties = properties
+	return c
+}
+
+// WithCustomID sets CustomEvent's custom ID.
+func (c *CustomEvent) WithCustomID(customID string) *CustomEvent {
+	c.CustomID = customID
+	return c
+}
+
+// DecodeContent unmarshals CustomEvent's content into given value.
+func (c *CustomEvent) DecodeContent(v interface{}) error {
+	return json.Unmarshal(c.Content, v)
+}
+
+// Custom function converts Event object to CustomEvent object if Event's Type is "custom".
+// If Type is different or Event is malformed, then it returns nil.
+func (e *Event) Custom() *CustomEvent {
+	if e.Type != "custom" {
+		return nil
+	}
+	var c CustomEvent
+
+	c.Event = *e
+	if e.Content != nil && !json.Valid(e.Content) {
+		return nil
+	}
+	c.Content = e.Content
+	return &c
+}
+
+// NewFilledForm creates FilledForm event with given form ID and answered fields, visible to all chat users.
+func NewFilledForm(formID string, fields ...FilledFormField) *FilledForm {
+	return &FilledForm{
+		Event: Event{
+			Type:       "filled_form",
+			Recipients: RecipientsAll,
+		},
+		FormID: formID,
+		Fields: fields,
+	}
+}
@@ -0,0 +1,121 @@
+package objects_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/livechat/lc-sdk-go/v2/objects"
+)
+
+type orderPlaced struct {
+	OrderID string `json:"order_id"`
+	Total   int    `json:"total"`
+}
+
+func TestCustomEventRoundTrip(t *testing.T) {
+	ce, err := objects.NewCustomEvent(orderPlaced{"ord-1", 100})
+	if err != nil {
+		t.Fatalf("NewCustomEvent failed: %v", err)
+	}
+	if err := objects.ValidateEvent(ce.WithCustomID("custom")); err != nil {
+		t.Errorf("CustomEvent should be valid: %v", err)
+	}
+
+	raw, err := json.Marshal(ce)
+	if err != nil {
+		t.Fatalf("couldn't marshal custom event: %v", err)
+	}
+	var e objects.Event
+	if err := json.Unmarshal(raw, &e); err != nil {
+		t.Fatalf("couldn't unmarshal custom event: %v", err)
+	}
+
+	custom := e.Custom()
+	if custom == nil {
+		t.Fatalf("Custom() returned nil")
+	}
+	var order orderPlaced
+	if err := custom.DecodeContent(&order); err != nil || order.OrderID != "ord-1" || order.Total != 100 {
+		t.Errorf("invalid custom event content: %v, %v", order, err)
+	}
+	if e.Message() != nil {
+		t.Errorf("Message() should return nil for custom event")
+	}
+}
+
+func TestValidateEventAcceptsFilledForm(t *testing.T) {
+	f := objects.NewFilledForm("form_id", objects.FilledFormField{Label: "Your name", Type: "name", Value: "John"})
+	if err := objects.ValidateEvent(f); err != nil {
+		t.Errorf("FilledForm should be valid: %v", err)
+	}
+	if err := objects.ValidateEvent(objects.NewFilledForm("form_id")); err == nil {
+		t.Errorf("FilledForm without fields should be rejected")
+	}
+}
+
+func TestSystemMessageAccessor(t *testing.T) {
+	raw := `{"type":"system_message","system_message_type":"routing.assigned","text":"Chat assigned","text_vars":{"agent":"John"}}`
+	var e objects.Event
+	if err := json.Unmarshal([]byte(raw), &e); err != nil {
+		t.Fatalf("couldn't unmarshal system message: %v", err)
+	}
+	sm := e.SystemMessage()
+	if sm == nil || sm.Type != "routing.assigned" || sm.Text != "Chat assigned" || sm.TextVars["agent"] != "John" {
+		t.Errorf("invalid system message: %v", sm)
+	}
+}
+
+func TestEventDecodeUsesRegisteredDecoders(t *testing.T) {
+	var e objects.Event
+	if err := json.Unmarshal([]byte(`{"type":"message","text":"hello"}`), &e); err != nil {
+		t.Fatalf("couldn't unmarshal message: %v", err)
+	}
+	decoded, err := e.Decode()
+	if m, ok := decoded.(*objects.Message); !ok || err != nil || m.Text != "hello" {
+		t.Errorf("invalid decoded message: %v, %v", decoded, err)
+	}
+
+	objects.RegisterEventDecoder("order_placed", func(e *objects.Event) (interface{}, error) {
+		var o orderPlaced
+		if err := json.Unmarshal(e.Content, &o); err != nil {
+			return nil, err
+		}
+		return &o, nil
+	})
+	defer objects.UnregisterEventDecoder("order_placed")
+	if err := json.Unmarshal([]byte(`{"type":"order_placed","content":{"order_id":"ord-2"}}`), &e); err != nil {
+		t.Fatalf("couldn't unmarshal order event: %v", err)
+	}
+	decoded, err = e.Decode()
+	if o, ok := decoded.(*orderPlaced); !ok || err != nil || o.OrderID != "ord-2" {
+		t.Errorf("invalid decoded order: %v, %v", decoded, err)
+	}
+
+	e = objects.Event{Type: "unknown"}
+	if _, err := e.Decode(); err == nil {
+		t.Errorf("Decode should fail for unknown event type")
+	}
+
+	objects.RegisterEventDecoder("failing", func(*objects.Event) (interface{}, error) {
+		return nil, errors.New("failure")
+	})
+	defer objects.UnregisterEventDecoder("failing")
+	e = objects.Event{Type: "failing"}
+	if _, err := e.Decode(); err == nil || err.Error() != "failure" {
+		t.Errorf("Decode should return decoder error: %v", err)
+	}
+}
+
+func TestMarshaledEventsOmitDecoderOnlyFields(t *testing.T) {
+	raw, err := json.Marshal(objects.NewMessage("hi"))
+	if err != nil {
+		t.Fatalf("couldn't marshal message: %v", err)
+	}
+	for _, field := range []string{"form_id", "content", "system_message_type", "text_vars"} {
+		if strings.Contains(string(raw), `"`+field+`"`) {
+			t.Errorf("message should not contain %s: %s", field, raw)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package objects
+
+import (
+	"fmt"
+	"sync"
+)
+
+// The EventDecoder type is used to convert generic Event into a specific event structure.
+type EventDecoder func(e *Event) (interface{}, error)
+
+var (
+	eventDecoders   = make(map[string]EventDecoder)
+	eventDecodersMu sync.RWMutex
+)
+
+func init() {
+	RegisterEventDecoder("message", func(e *Event) (interface{}, error) {
+		if m := e.Message(); m != nil {
+			return m, nil
+		}
+		return nil, errMalformedEvent(e)
+	})
+	RegisterEventDecoder("file", func(e *Event) (interface{}, error) {
+		if f := e.File(); f != nil {
+			return f, nil
+		}
+		return nil, errMalformedEvent(e)
+	})
+	RegisterEventDecoder("rich_message", func(e *Event) (interface{}, error) {
+		if rm := e.RichMessage(); rm != nil {
+			return rm, nil
+		}
+		return nil, errMalformedEvent(e)
+	})
+	RegisterEventDecoder("system_message", func(e *Event) (interface{}, error) {
+		if sm := e.SystemMessage(); sm != nil {
+			return sm, nil
+		}
+		return nil, errMalformedEvent(e)
+	})
+	RegisterEventDecoder("filled_form", func(e *Event) (interface{}, error) {
+		if f := e.FilledForm(); f != nil {
+			return f, nil
+		}
+		return nil, errMalformedEvent(e)
+	})
+	RegisterEventDecoder("custom", func(e *Event) (interface{}, error) {
+		if c := e.Custom(); c != nil {
+			return c, nil
+		}
+		return nil, errMalformedEvent(e)
+	})
+}
+
+func errMalformedEvent(e *Event) error {
+	return fmt.Errorf("malformed %s event", e.Type)
+}
+
+// RegisterEventDecoder registers EventDecoder for given event type, replacing the previous one if any.
+//
+// Decoders for built-in event types (message, file, rich_message, system_message, filled_form and custom)
+// are registered by default. Registering own decoder for "custom" type allows to decode custom events'
+// content into application specific structures.
+func RegisterEventDecoder(eventType string, decoder EventDecoder) {
+	eventDecodersMu.Lock()
+	defer eventDecodersMu.Unlock()
+
+	eventDecoders[eventType] = decoder
+}
+
+// UnregisterEventDecoder removes EventDecoder registered for given event type.
+func UnregisterEventDecoder(eventType string) {
+	eventDecodersMu.Lock()
+	defer eventDecodersMu.Unlock()
+
+	delete(eventDecoders, eventType)
+}
+
+// Decode converts Event into specific event structure with EventDecoder registered for Event's Type.
+func (e *Event) Decode() (interface{}, error) {
+	eventDecodersMu.RLock()
+	decoder, exists := eventDecoders[e.Type]
+	eventDecodersMu.RUnlock()
+
+	if !exists {
+		return nil, fmt.Errorf("no decoder for event type: %q", e.Type)
+	}
+	return decoder(e)
+}
@@ -176,6 +176,7 @@ type Thread struct {
 	Properties       Properties `json:"properties"`
 	Access           Access     `json:"access"`
 	Events           []*Event   `json:"events"`
+	Tags             []string   `json:"tags,omitempty"`
 	PreviousThreadID string     `json:"previous_thread_id"`
 	NextThreadID     string     `json:"next_thread_id"`
 	CreatedAt        time.Time  `json:"created_at"`
@@ -189,7 +190,7 @@ type Access struct {
 // Agent represents LiveChat agent.
 type Agent struct {
 	*User
-	RoutingStatus string `json:"routing_status"`
+	RoutingStatus RoutingStatus `json:"routing_status"`
 }
 
 // Customer represents LiveChat customer.
@@ -204,10 +205,10 @@ type Customer struct {
 		GreetingsShownCount    int `json:"greetings_shown_count"`
 		GreetingsAcceptedCount int `json:"greetings_accepted_count"`
 	} `json:"statistics"`
-	AgentLastEventCreatedAt    time.Time           `json:"agent_last_event_created_at"`
-	CustomerLastEventCreatedAt time.Time           `json:"customer_last_event_created_at"`
-	CreatedAt                  time.Time           `json:"created_at"`
-	SessionFields              []map[string]string `json:"session_fields"`
+	AgentLastEventCreatedAt    time.Time     `json:"agent_last_event_created_at"`
+	CustomerLastEventCreatedAt time.Time     `json:"customer_last_event_created_at"`
+	CreatedAt                  time.Time     `json:"created_at"`
+	SessionFields              SessionFields `json:"session_fields"`
 }
 
 // ThreadSummary represents a short summary of a thread
@@ -245,7 +246,7 @@ type InitialChat struct {
 	Thread     *InitialThread `json:"thread,omitempty"`
 }
 
-// Validate checks if there are no unsupported event types in InitialChat Thread
+// Validate checks if there are no unsupported or malformed events in InitialChat Thread
 func (chat *InitialChat) Validate() error {
 	if chat.Thread != nil {
 		for _, e := range chat.Thread.Events {
@@ -258,23 +259,40 @@ func (chat *InitialChat) Validate() error {
 }
 
 // ValidateEvent checks if given interface resolves into supported event type
+// and if the event is well-formed (see Validate methods of particular event types).
 func ValidateEvent(e interface{}) error {
 	switch v := e.(type) {
 	case *Event:
+		return v.Validate()
 	case *File:
+		return v.Validate()
 	case *Message:
+		return v.Validate()
 	case *RichMessage:
+		return v.Validate()
 	case *SystemMessage:
+		return v.Validate()
+	case *FilledForm:
+		return v.Validate()
+	case *CustomEvent:
+		return v.Validate()
 	case Event:
+		return v.Validate()
 	case File:
+		return v.Validate()
 	case Message:
+		return v.Validate()
 	case RichMessage:
+		return v.Validate()
 	case SystemMessage:
+		return v.Validate()
+	case FilledForm:
+		return v.Validate()
+	case CustomEvent:
+		return v.Validate()
 	default:
 		return fmt.Errorf("event type %T not supported", v)
 	}
-
-	return nil
 }
 
 // InitialThread represents initial chat thread used in StartChat or ActivateChat.
@@ -294,6 +312,10 @@ type eventSpecific struct {
 	TemplateID  json.RawMessage `json:"template_id"`
 	Elements    json.RawMessage `json:"elements"`
 	Postback    json.RawMessage `json:"postback"`
+	FormID      json.RawMessage `json:"form_id,omitempty"`
+	Content     json.RawMessage `json:"content,omitempty"`
+	SystemType  json.RawMessage `json:"system_message_type,omitempty"`
+	TextVars    json.RawMessage `json:"text_vars,omitempty"`
 }
 
 // Event represents base of all LiveChat chat events.
@@ -312,14 +334,19 @@ type Event struct {
 
 // FilledForm represents LiveChat filled form event.
 type FilledForm struct {
-	Fields []struct {
-		Label string `json:"label"`
-		Type  string `json:"type"`
-		Value string `json:"value"`
-	} `json:"fields"`
+	FormID string            `json:"form_id,omitempty"`
+	Fields []FilledFormField `json:"fields"`
 	Event
 }
 
+// FilledFormField represents single answered field of LiveChat filled form event.
+type FilledFormField struct {
+	ID    string `json:"id,omitempty"`
+	Label string `json:"label"`
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
 // FilledForm function converts Event object to FilledForm object if Event's Type is "filled_form".
 // If Type is different or Event is malformed, then it returns nil.
 func (e *Event) FilledForm() *FilledForm {
@@ -332,6 +359,9 @@ func (e *Event) FilledForm() *FilledForm {
 	if err := json.Unmarshal(e.Fields, &f.Fields); err != nil {
 		return nil
 	}
+	if err := unmarshalOptionalRawField(e.FormID, &f.FormID); err != nil {
+		return nil
+	}
 	return &f
 }
 
@@ -377,6 +407,27 @@ type SystemMessage struct {
 	TextVars map[string]string `json:"text_vars,omitempty"`
 }
 
+// SystemMessage function converts Event object to SystemMessage object if Event's Type is "system_message".
+// If Type is different or Event is malformed, then it returns nil.
+func (e *Event) SystemMessage() *SystemMessage {
+	if e.Type != "system_message" {
+		return nil
+	}
+	var sm SystemMessage
+
+	sm.Event = *e
+	if err := unmarshalOptionalRawField(e.SystemType, &sm.Type); err != nil {
+		return nil
+	}
+	if err := unmarshalOptionalRawField(e.Text, &sm.Text); err != nil {
+		return nil
+	}
+	if err := unmarshalOptionalRawField(e.TextVars, &sm.TextVars); err != nil {
+		return nil
+	}
+	return &sm
+}
+
 // File represents LiveChat file event
 type File struct {
 	Event
@@ -0,0 +1,128 @@
+package objects
+
+// Supported rich message templates.
+const (
+	TemplateCards        = "cards"
+	TemplateQuickReplies = "quick_replies"
+	TemplateSticker      = "sticker"
+)
+
+// Supported rich message button types.
+const (
+	ButtonMessage = "message"
+	ButtonURL     = "url"
+	ButtonWebview = "webview"
+	ButtonPhone   = "phone"
+	ButtonCancel  = "cancel"
+)
+
+// Supported heights of webview opened by rich message button.
+const (
+	WebviewCompact = "compact"
+	WebviewFull    = "full"
+	WebviewTall    = "tall"
+)
+
+// NewCards creates RichMessage of cards template with given elements, visible to all chat users.
+// Nil cards are skipped.
+func NewCards(cards ...*RichMessageElement) *RichMessage {
+	elements := make([]RichMessageElement, 0, len(cards))
+	for _, c := range cards {
+		if c == nil {
+			continue
+		}
+		elements = append(elements, *c)
+	}
+	return NewRichMessage(TemplateCards, elements...)
+}
+
+// NewQuickReplies creates RichMessage of quick_replies template with given title and buttons,
+// visible to all chat users.
+func NewQuickReplies(title string, buttons ...RichMessageButton) *RichMessage {
+	return NewRichMessage(TemplateQuickReplies, RichMessageElement{
+		Title:   title,
+		Buttons: buttons,
+	})
+}
+
+// NewSticker creates RichMessage of sticker template with image of given URL, visible to all chat users.
+func NewSticker(imageURL string) *RichMessage {
+	return NewRichMessage(TemplateSticker, RichMessageElement{
+		Image: &RichMessageImage{URL: imageURL},
+	})
+}
+
+// NewCard creates RichMessageElement with given title, to be used with NewCards.
+func NewCard(title string) *RichMessageElement {
+	return &RichMessageElement{Title: title}
+}
+
+// WithSubtitle sets RichMessageElement's subtitle.
+func (el *RichMessageElement) WithSubtitle(subtitle string) *RichMessageElement {
+	el.Subtitle = subtitle
+	return el
+}
+
+// WithImage sets RichMessageElement's image to the one of given URL.
+func (el *RichMessageElement) WithImage(imageURL string) *RichMessageElement {
+	el.Image = &RichMessageImage{URL: imageURL}
+	return el
+}
+
+// WithButtons appends given buttons to RichMessageElement.
+func (el *RichMessageElement) WithButtons(buttons ...RichMessageButton) *RichMessageElement {
+	el.Buttons = append(el.Buttons, buttons...)
+	return el
+}
+
+// NewMessageButton creates button that sends its text as a message when clicked.
+func NewMessageButton(text, postbackID string) RichMessageButton {
+	return RichMessageButton{
+		Text:       text,
+		Type:       ButtonMessage,
+		Value:      text,
+		PostbackID: postbackID,
+	}
+}
+
+// NewURLButton creates button that opens given URL in a new browser tab when clicked.
+func NewURLButton(text, url, postbackID string) RichMessageButton {
+	return RichMessageButton{
+		Text:       text,
+		Type:       ButtonURL,
+		Value:      url,
+		PostbackID: postbackID,
+		Target:     "new",
+	}
+}
+
+// NewWebviewButton creates button that opens given URL in a webview of given height when clicked.
+// See WebviewCompact, WebviewFull and WebviewTall for allowed heights.
+func NewWebviewButton(text, url, height, postbackID string) RichMessageButton {
+	return RichMessageButton{
+		Text:          text,
+		Type:          ButtonWebview,
+		Value:         url,
+		PostbackID:    postbackID,
+		WebviewHeight: height,
+	}
+}
+
+// NewPhoneButton creates button that dials given phone number when clicked.
+func NewPhoneButton(text, phoneNumber, postbackID string) RichMessageButton {
+	return RichMessageButton{
+		Text:       text,
+		Type:       ButtonPhone,
+		Value:      phoneNumber,
+		PostbackID: postbackID,
+	}
+}
+
+// NewCancelButton creates button that only sends postback when clicked.
+func NewCancelButton(text, postbackID string) RichMessageButton {
+	return RichMessageButton{
+		Text:       text,
+		Type:       ButtonCancel,
+		PostbackID: postbackID,
+	}
+}
@@ -0,0 +1,69 @@
+package objects_test
+
+import (
+	"testing"
+
+	"github.com/livechat/lc-sdk-go/v2/objects"
+)
+
+func TestNewCardsBuildsValidRichMessage(t *testing.T) {
+	rm := objects.NewCards(
+		objects.NewCard("Shoes").
+			WithSubtitle("Running shoes").
+			WithImage("https://example.com/shoes.png").
+			WithButtons(
+				objects.NewMessageButton("Buy", "buy_shoes"),
+				objects.NewURLButton("Details", "https://example.com/shoes", "shoes_details"),
+				objects.NewWebviewButton("Size chart", "https://example.com/sizes", objects.WebviewTall, "shoes_sizes"),
+			),
+		objects.NewCard("Socks").WithButtons(objects.NewPhoneButton("Call us", "+48123456789", "call")),
+	)
+
+	if rm.Type != "rich_message" || rm.TemplateID != objects.TemplateCards {
+		t.Errorf("RichMessage type invalid: %v, %v", rm.Type, rm.TemplateID)
+	}
+	if len(rm.Elements) != 2 || len(rm.Elements[0].Buttons) != 3 {
+		t.Errorf("RichMessage.Elements invalid: %v", rm.Elements)
+	}
+	if b := rm.Elements[0].Buttons[0]; b.Type != objects.ButtonMessage || b.Value != "Buy" || b.PostbackID != "buy_shoes" {
+		t.Errorf("message button invalid: %v", b)
+	}
+	if err := rm.Validate(); err != nil {
+		t.Errorf("RichMessage should be valid: %v", err)
+	}
+}
+
+func TestNewCardsSkipsNilCards(t *testing.T) {
+	rm := objects.NewCards(nil, objects.NewCard("Shoes"), nil)
+	if len(rm.Elements) != 1 || rm.Elements[0].Title != "Shoes" {
+		t.Errorf("RichMessage.Elements invalid: %v", rm.Elements)
+	}
+}
+
+func TestNewQuickRepliesBuildsValidRichMessage(t *testing.T) {
+	rm := objects.NewQuickReplies("Was it helpful?",
+		objects.NewMessageButton("Yes", "helpful_yes"),
+		objects.NewMessageButton("No", "helpful_no"),
+		objects.NewCancelButton("Skip", "helpful_skip"),
+	).ToAgentsOnly()
+
+	if rm.TemplateID != objects.TemplateQuickReplies || len(rm.Elements) != 1 || len(rm.Elements[0].Buttons) != 3 {
+		t.Errorf("RichMessage invalid: %v", rm)
+	}
+	if rm.Recipients != objects.RecipientsAgents {
+		t.Errorf("RichMessage.Recipients invalid: %v", rm.Recipients)
+	}
+	if err := objects.ValidateEvent(rm); err != nil {
+		t.Errorf("RichMessage should be valid: %v", err)
+	}
+}
+
+func TestNewStickerBuildsValidRichMessage(t *testing.T) {
+	rm := objects.NewSticker("https://example.com/sticker.png")
+	if err := objects.ValidateEvent(rm); err != nil {
+		t.Errorf("RichMessage should be valid: %v", err)
+	}
+	if err := objects.ValidateEvent(objects.NewSticker("sticker.png")); err == nil {
+		t.Errorf("Sticker with relative URL should be rejected")
+	}
+}
@@ -0,0 +1,55 @@
+package objects
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// RoutingStatus represents availability of an agent or a bot for chat routing.
+type RoutingStatus string
+
+// Possible values of RoutingStatus.
+const (
+	// AcceptingChats - agent or bot is assigned new chats.
+	AcceptingChats RoutingStatus = "accepting_chats"
+	// NotAcceptingChats - agent or bot is not assigned new chats, yet it still occupies a seat.
+	NotAcceptingChats RoutingStatus = "not_accepting_chats"
+	// Offline - agent or bot is not assigned new chats and doesn't occupy a seat.
+	Offline RoutingStatus = "offline"
+)
+
+// ParseRoutingStatus converts given string to RoutingStatus. Legacy values with spaces
+// instead of underscores (ie. "accepting chats") are accepted as well.
+func ParseRoutingStatus(s string) (RoutingStatus, error) {
+	rs := RoutingStatus(strings.Replace(s, " ", "_", -1))
+	if err := rs.Validate(); err != nil {
+		return "", err
+	}
+	return rs, nil
+}
+
+// Validate checks if RoutingStatus has one of supported values.
+func (rs RoutingStatus) Validate() error {
+	switch rs {
+	case AcceptingChats, NotAcceptingChats, Offline:
+		return nil
+	}
+	return fmt.Errorf("invalid routing status: %q", string(rs))
+}
+
+// UnmarshalJSON implements json.Unmarshaler interface for RoutingStatus.
+//
+// Legacy values are normalized, while unknown values are kept intact.
+func (rs *RoutingStatus) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	if parsed, err := ParseRoutingStatus(s); err == nil {
+		*rs = parsed
+		return nil
+	}
+	*rs = RoutingStatus(s)
+	return nil
+}
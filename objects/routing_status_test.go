@@ -0,0 +1,38 @@
+package objects_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/livechat/lc-sdk-go/v2/objects"
+)
+
+func TestParseRoutingStatus(t *testing.T) {
+	for input, expected := range map[string]objects.RoutingStatus{
+		"accepting_chats":     objects.AcceptingChats,
+		"not accepting chats": objects.NotAcceptingChats,
+		"offline":             objects.Offline,
+	} {
+		if rs, err := objects.ParseRoutingStatus(input); rs != expected || err != nil {
+			t.Errorf("invalid routing status for %q: %v, %v", input, rs, err)
+		}
+	}
+
+	if _, err := objects.ParseRoutingStatus("busy"); err == nil {
+		t.Errorf("unknown routing status should be rejected")
+	}
+}
+
+func TestRoutingStatusUnmarshalJSON(t *testing.T) {
+	var statuses []objects.RoutingStatus
+	if err := json.Unmarshal([]byte(`["accepting chats", "offline", "away"]`), &statuses); err != nil {
+		t.Fatalf("couldn't unmarshal routing statuses: %v", err)
+	}
+
+	if statuses[0] != objects.AcceptingChats || statuses[1] != objects.Offline || statuses[2] != "away" {
+		t.Errorf("invalid routing statuses: %v", statuses)
+	}
+	if statuses[2].Validate() == nil {
+		t.Errorf("unknown routing status should not be valid")
+	}
+}
@@ -0,0 +1,175 @@
+package objects
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"unicode/utf8"
+)
+
+// Limits of customer session fields enforced by the platform.
+const (
+	MaxSessionFields           = 100
+	MaxSessionFieldKeyLength   = 256
+	MaxSessionFieldValueLength = 4096
+)
+
+// SessionFields represents ordered key-value pairs describing customer's session.
+//
+// On the wire each field is a separate single-key object, ie. [{"plan": "pro"}, {"crm_id": "42"}].
+type SessionFields []map[string]string
+
+// NewSessionFields creates SessionFields from given key-value pairs, ie. NewSessionFields("plan", "pro").
+// Odd trailing key is set to an empty value.
+func NewSessionFields(keyValues ...string) SessionFields {
+	sf := SessionFields{}
+	for i := 0; i < len(keyValues); i += 2 {
+		value := ""
+		if i+1 < len(keyValues) {
+			value = keyValues[i+1]
+		}
+		sf.Set(keyValues[i], value)
+	}
+	return sf
+}
+
+// Get returns value of given field and information whether it was found.
+func (sf SessionFields) Get(key string) (string, bool) {
+	for _, field := range sf {
+		if v, exists := field[key]; exists {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// Keys returns keys of fields in order.
+func (sf SessionFields) Keys() []string {
+	var keys []string
+	for _, field := range sf {
+		keys = append(keys, fieldKeys(field)...)
+	}
+	return keys
+}
+
+// Len returns number of fields.
+func (sf SessionFields) Len() int {
+	n := 0
+	for _, field := range sf {
+		n += len(field)
+	}
+	return n
+}
+
+// Set sets value of given field, keeping its position if it already exists or appending it otherwise.
+func (sf *SessionFields) Set(key, value string) {
+	for _, field := range *sf {
+		if _, exists := field[key]; exists {
+			field[key] = value
+			return
+		}
+	}
+	*sf = append(*sf, map[string]string{key: value})
+}
+
+// Delete removes all occurrences of given field and returns information whether it was found.
+func (sf *SessionFields) Delete(key string) bool {
+	found := false
+	fields := (*sf)[:0]
+	for _, field := range *sf {
+		if _, exists := field[key]; exists {
+			found = true
+			if len(field) == 1 {
+				continue
+			}
+			delete(field, key)
+		}
+		fields = append(fields, field)
+	}
+	*sf = fields
+	return found
+}
+
+// Merge copies fields from other into sf. Existing fields are overwritten only if overwrite is true,
+// new fields are appended in order they appear in other.
+func (sf *SessionFields) Merge(other SessionFields, overwrite bool) {
+	for _, key := range other.Keys() {
+		value, _ := other.Get(key)
+		if _, exists := sf.Get(key); exists && !overwrite {
+			continue
+		}
+		sf.Set(key, value)
+	}
+}
+
+// Clone returns deep copy of sf. Duplicated keys, which may be present in fields stored by the platform,
+// are dropped - only the first occurrence of each key is kept, as returned by Get.
+func (sf SessionFields) Clone() SessionFields {
+	if sf == nil {
+		return nil
+	}
+	clone := make(SessionFields, 0, len(sf))
+	seen := make(map[string]bool)
+	for _, field := range sf {
+		c := make(map[string]string, len(field))
+		for k, v := range field {
+			if !seen[k] {
+				seen[k] = true
+				c[k] = v
+			}
+		}
+		if len(c) > 0 {
+			clone = append(clone, c)
+		}
+	}
+	return clone
+}
+
+// Validate checks if SessionFields have no empty or duplicated keys and fit in platform limits.
+func (sf SessionFields) Validate() error {
+	if n := sf.Len(); n > MaxSessionFields {
+		return fmt.Errorf("too many session fields: %d, max: %d", n, MaxSessionFields)
+	}
+	seen := make(map[string]bool)
+	for _, key := range sf.Keys() {
+		if key == "" {
+			return fmt.Errorf("session field key cannot be empty")
+		}
+		if seen[key] {
+			return fmt.Errorf("duplicated session field: %q", key)
+		}
+		seen[key] = true
+		if utf8.RuneCountInString(key) > MaxSessionFieldKeyLength {
+			return fmt.Errorf("session field key %q too long, max: %d", key, MaxSessionFieldKeyLength)
+		}
+		if v, _ := sf.Get(key); utf8.RuneCountInString(v) > MaxSessionFieldValueLength {
+			return fmt.Errorf("session field %q value too long, max: %d", key, MaxSessionFieldValueLength)
+		}
+	}
+	return nil
+}
+
+// MarshalJSON encodes SessionFields as list of single-key objects.
+func (sf SessionFields) MarshalJSON() ([]byte, error) {
+	if sf == nil {
+		return []byte("null"), nil
+	}
+	fields := make([]map[string]string, 0, sf.Len())
+	for _, field := range sf {
+		for _, k := range fieldKeys(field) {
+			fields = append(fields, map[string]string{k: field[k]})
+		}
+	}
+	return json.Marshal(fields)
+}
+
+// fieldKeys returns keys of single wire object. Objects with more than one key are not created by
+// SessionFields methods, their keys are sorted to keep the order stable.
+func fieldKeys(field map[string]string) []string {
+	keys := make([]string, 0, len(field))
+	for k := range field {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,106 @@
+package objects_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/livechat/lc-sdk-go/v2/objects"
+)
+
+func TestSessionFieldsAccessors(t *testing.T) {
+	sf := objects.NewSessionFields("plan", "pro", "crm_id", "42")
+	sf.Set("plan", "enterprise")
+	sf.Set("source", "ads")
+
+	if v, ok := sf.Get("plan"); !ok || v != "enterprise" {
+		t.Errorf("invalid plan: %v, %v", v, ok)
+	}
+	if keys := strings.Join(sf.Keys(), ","); keys != "plan,crm_id,source" {
+		t.Errorf("invalid keys order: %v", keys)
+	}
+	if !sf.Delete("crm_id") || sf.Delete("crm_id") {
+		t.Errorf("field should be deleted once")
+	}
+	if _, ok := sf.Get("crm_id"); ok || sf.Len() != 2 {
+		t.Errorf("invalid fields after delete: %v", sf)
+	}
+}
+
+func TestSessionFieldsMerge(t *testing.T) {
+	other := objects.NewSessionFields("plan", "free", "crm_id", "42")
+
+	kept := objects.NewSessionFields("plan", "pro")
+	kept.Merge(other, false)
+	if v, _ := kept.Get("plan"); v != "pro" || strings.Join(kept.Keys(), ",") != "plan,crm_id" {
+		t.Errorf("invalid merge without overwrite: %v", kept)
+	}
+
+	overwritten := objects.NewSessionFields("plan", "pro")
+	overwritten.Merge(other, true)
+	if v, _ := overwritten.Get("plan"); v != "free" {
+		t.Errorf("invalid merge with overwrite: %v", overwritten)
+	}
+
+	var empty objects.SessionFields
+	empty.Merge(other, false)
+	if empty.Len() != 2 {
+		t.Errorf("invalid merge into empty fields: %v", empty)
+	}
+}
+
+func TestSessionFieldsCloneDropsDuplicatedKeys(t *testing.T) {
+	stored := objects.SessionFields{{"plan": "pro"}, {"crm_id": "42"}, {"plan": "free"}}
+
+	clone := stored.Clone()
+	if v, _ := clone.Get("plan"); v != "pro" || strings.Join(clone.Keys(), ",") != "plan,crm_id" {
+		t.Errorf("invalid clone: %v", clone)
+	}
+	if err := clone.Validate(); err != nil {
+		t.Errorf("clone should be valid: %v", err)
+	}
+	if len(stored) != 3 {
+		t.Errorf("original fields should not be modified: %v", stored)
+	}
+}
+
+func TestSessionFieldsValidate(t *testing.T) {
+	if err := objects.NewSessionFields("plan", "pro").Validate(); err != nil {
+		t.Errorf("fields should be valid: %v", err)
+	}
+
+	tooMany := objects.SessionFields{}
+	for i := 0; i <= objects.MaxSessionFields; i++ {
+		tooMany.Set(strings.Repeat("k", i+1), "v")
+	}
+	for _, sf := range []objects.SessionFields{
+		{{"plan": "pro"}, {"plan": "free"}},
+		{{"": "empty"}},
+		{{strings.Repeat("k", objects.MaxSessionFieldKeyLength+1): "v"}},
+		{{"k": strings.Repeat("v", objects.MaxSessionFieldValueLength+1)}},
+		tooMany,
+	} {
+		if err := sf.Validate(); err == nil {
+			t.Errorf("fields should be invalid: %.40v", sf)
+		}
+	}
+}
+
+func TestSessionFieldsJSON(t *testing.T) {
+	sf := objects.SessionFields{{"b": "2", "a": "1"}, {"c": "3"}}
+	raw, err := json.Marshal(sf)
+	if err != nil {
+		t.Fatalf("couldn't marshal session fields: %v", err)
+	}
+	if string(raw) != `[{"a":"1"},{"b":"2"},{"c":"3"}]` {
+		t.Errorf("invalid wire format: %s", raw)
+	}
+
+	var customer objects.Customer
+	if err := json.Unmarshal([]byte(`{"id": "c1", "session_fields": [{"plan": "pro"}]}`), &customer); err != nil {
+		t.Fatalf("couldn't unmarshal customer: %v", err)
+	}
+	if v, _ := customer.SessionFields.Get("plan"); v != "pro" {
+		t.Errorf("invalid session fields: %v", customer.SessionFields)
+	}
+}
@@ -0,0 +1,211 @@
+package objects
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+)
+
+// Limits of rich message structure.
+const (
+	MaxRichMessageElements = 10
+	MaxRichMessageButtons  = 13
+)
+
+var errNilEvent = errors.New("event cannot be nil")
+
+func (e *Event) validate(eventType string) error {
+	if e.Type != "" && e.Type != eventType {
+		return fmt.Errorf("invalid event type: %q, expected: %q", e.Type, eventType)
+	}
+	switch e.Recipients {
+	case "", RecipientsAll, RecipientsAgents:
+	default:
+		return fmt.Errorf("invalid event recipients: %q", e.Recipients)
+	}
+	return nil
+}
+
+func validateURL(field, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("%s is not a valid URL: %v", field, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf("%s is not an absolute http(s) URL: %q", field, rawURL)
+	}
+	return nil
+}
+
+// Validate checks if Event has valid recipients.
+func (e *Event) Validate() error {
+	if e == nil {
+		return errNilEvent
+	}
+	return e.validate(e.Type)
+}
+
+// Validate checks if Message has valid type, recipients and either text or postback.
+func (m *Message) Validate() error {
+	if m == nil {
+		return errNilEvent
+	}
+	if err := m.Event.validate("message"); err != nil {
+		return err
+	}
+	if m.Text == "" && m.Postback == nil {
+		return errors.New("message text cannot be empty")
+	}
+	return nil
+}
+
+// Validate checks if File has valid type, recipients and URL.
+func (f *File) Validate() error {
+	if f == nil {
+		return errNilEvent
+	}
+	if err := f.Event.validate("file"); err != nil {
+		return err
+	}
+	return validateURL("file url", f.URL)
+}
+
+// Validate checks if SystemMessage has valid type, recipients and either text or system message type.
+func (sm *SystemMessage) Validate() error {
+	if sm == nil {
+		return errNilEvent
+	}
+	if err := sm.Event.validate("system_message"); err != nil {
+		return err
+	}
+	if sm.Text == "" && sm.Type == "" {
+		return errors.New("system message must have text or system message type")
+	}
+	return nil
+}
+
+// Validate checks if RichMessage has valid type, recipients, template and elements.
+func (rm *RichMessage) Validate() error {
+	if rm == nil {
+		return errNilEvent
+	}
+	if err := rm.Event.validate("rich_message"); err != nil {
+		return err
+	}
+
+	switch rm.TemplateID {
+	case TemplateCards, TemplateQuickReplies:
+	case TemplateSticker:
+		if len(rm.Elements) != 1 || rm.Elements[0].Image == nil {
+			return errors.New("sticker rich message must have exactly one element with image")
+		}
+	default:
+		return fmt.Errorf("unsupported rich message template: %q", rm.TemplateID)
+	}
+
+	if len(rm.Elements) == 0 {
+		return errors.New("rich message must have at least one element")
+	}
+	if len(rm.Elements) > MaxRichMessageElements {
+		return fmt.Errorf("rich message cannot have more than %d elements", MaxRichMessageElements)
+	}
+	for i := range rm.Elements {
+		if err := rm.Elements[i].Validate(); err != nil {
+			return fmt.Errorf("invalid rich message element %d: %v", i, err)
+		}
+	}
+	return nil
+}
+
+// Validate checks if RichMessageElement has valid image and buttons.
+func (el *RichMessageElement) Validate() error {
+	if el.Title == "" && el.Subtitle == "" && el.Image == nil && len(el.Buttons) == 0 {
+		return errors.New("element cannot be empty")
+	}
+	if el.Image != nil {
+		if err := validateURL("image url", el.Image.URL); err != nil {
+			return err
+		}
+	}
+	if len(el.Buttons) > MaxRichMessageButtons {
+		return fmt.Errorf("element cannot have more than %d buttons", MaxRichMessageButtons)
+	}
+	for i := range el.Buttons {
+		if err := el.Buttons[i].Validate(); err != nil {
+			return fmt.Errorf("invalid button %d: %v", i, err)
+		}
+	}
+	return nil
+}
+
+// Validate checks if RichMessageButton has text and value matching its type.
+func (b *RichMessageButton) Validate() error {
+	if b.Text == "" {
+		return errors.New("button text cannot be empty")
+	}
+	switch b.Type {
+	case "", ButtonMessage, ButtonCancel:
+	case ButtonURL:
+		if err := validateURL("button value", b.Value); err != nil {
+			return err
+		}
+	case ButtonWebview:
+		if err := validateURL("button value", b.Value); err != nil {
+			return err
+		}
+		switch b.WebviewHeight {
+		case "", WebviewCompact, WebviewFull, WebviewTall:
+		default:
+			return fmt.Errorf("invalid webview height: %q", b.WebviewHeight)
+		}
+	case ButtonPhone:
+		if b.Value == "" {
+			return errors.New("phone button value cannot be empty")
+		}
+	default:
+		return fmt.Errorf("unsupported button type: %q", b.Type)
+	}
+	switch b.Target {
+	case "", "new", "current":
+	default:
+		return fmt.Errorf("invalid button target: %q", b.Target)
+	}
+	return nil
+}
+
+// Validate checks if FilledForm has valid type, recipients and labeled fields.
+func (f *FilledForm) Validate() error {
+	if f == nil {
+		return errNilEvent
+	}
+	if err := f.Event.validate("filled_form"); err != nil {
+		return err
+	}
+	if len(f.Fields) == 0 {
+		return errors.New("filled form must have at least one field")
+	}
+	for i, field := range f.Fields {
+		if field.Type == "" {
+			return fmt.Errorf("filled form field %d has no type", i)
+		}
+	}
+	return nil
+}
+
+// Validate checks if CustomEvent has valid type, recipients and JSON content.
+func (c *CustomEvent) Validate() error {
+	if c == nil {
+		return errNilEvent
+	}
+	if err := c.Event.validate("custom"); err != nil {
+		return err
+	}
+	if len(c.Content) == 0 {
+		return errors.New("custom event content cannot be empty")
+	}
+	if !json.Valid(c.Content) {
+		return errors.New("custom event content is not a valid JSON")
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package objects_test
+
+import (
+	"testing"
+
+	"github.com/livechat/lc-sdk-go/v2/objects"
+)
+
+func TestNewMessageSetsTypeAndRecipients(t *testing.T) {
+	props := objects.Properties{"ns": {"key": "value"}}
+	m := objects.NewMessage("hello").ToAgentsOnly().WithProperties(props).WithCustomID("custom")
+
+	if m.Type != "message" {
+		t.Errorf("Message.Type invalid: %v", m.Type)
+	}
+	if m.Recipients != objects.RecipientsAgents {
+		t.Errorf("Message.Recipients invalid: %v", m.Recipients)
+	}
+	if m.Properties["ns"]["key"] != "value" {
+		t.Errorf("Message.Properties invalid: %v", m.Properties)
+	}
+	if m.CustomID != "custom" {
+		t.Errorf("Message.CustomID invalid: %v", m.CustomID)
+	}
+	if err := objects.ValidateEvent(m); err != nil {
+		t.Errorf("Message should be valid: %v", err)
+	}
+}
+
+func TestNewSystemMessageKeepsSystemMessageType(t *testing.T) {
+	sm := objects.NewSystemMessage("text", "routing.assigned").WithTextVars(map[string]string{"agent": "John"})
+
+	if sm.Event.Type != "system_message" {
+		t.Errorf("SystemMessage.Event.Type invalid: %v", sm.Event.Type)
+	}
+	if sm.Type != "routing.assigned" {
+		t.Errorf("SystemMessage.Type invalid: %v", sm.Type)
+	}
+	if err := objects.ValidateEvent(sm); err != nil {
+		t.Errorf("SystemMessage should be valid: %v", err)
+	}
+}
+
+func TestValidateEventRejectsMalformedEvents(t *testing.T) {
+	mismatchedType := objects.NewMessage("hello")
+	mismatchedType.Type = "file"
+	invalidRecipients := objects.NewMessage("hello")
+	invalidRecipients.Recipients = "customers"
+	tooManyElements := objects.NewRichMessage(objects.TemplateCards, make([]objects.RichMessageElement, objects.MaxRichMessageElements+1)...)
+	for i := range tooManyElements.Elements {
+		tooManyElements.Elements[i].Title = "title"
+	}
+
+	cases := map[string]interface{}{
+		"mismatched type":          mismatchedType,
+		"invalid recipients":       invalidRecipients,
+		"empty text":               objects.NewMessage(""),
+		"relative file url":        objects.NewFile("/file.png"),
+		"empty system message":     objects.NewSystemMessage("", ""),
+		"unknown template":         objects.NewRichMessage("carousel", objects.RichMessageElement{Title: "title"}),
+		"no elements":              objects.NewRichMessage(objects.TemplateCards),
+		"too many elements":        tooManyElements,
+		"sticker without image":    objects.NewRichMessage(objects.TemplateSticker, objects.RichMessageElement{Title: "title"}),
+		"url button without url":   objects.NewRichMessage(objects.TemplateCards, objects.RichMessageElement{Buttons: []objects.RichMessageButton{{Text: "Go", Type: "url"}}}),
+		"button without text":      objects.NewRichMessage(objects.TemplateCards, objects.RichMessageElement{Buttons: []objects.RichMessageButton{{Type: "message"}}}),
+		"invalid webview height":   objects.NewRichMessage(objects.TemplateCards, objects.RichMessageElement{Buttons: []objects.RichMessageButton{{Text: "Go", Type: "webview", Value: "https://example.com", WebviewHeight: "huge"}}}),
+		"nil message":              (*objects.Message)(nil),
+		"unsupported event struct": struct{}{},
+	}
+
+	for name, e := range cases {
+		if err := objects.ValidateEvent(e); err == nil {
+			t.Errorf("Event with %s should be rejected", name)
+		}
+	}
+}
+
+func TestValidateEventAcceptsWellFormedEvents(t *testing.T) {
+	cases := map[string]interface{}{
+		"base event":       objects.Event{},
+		"message postback": &objects.Message{Postback: &objects.Postback{ID: "id"}},
+		"file":             objects.NewFile("https://cdn.livechatinc.com/file.png"),
+		"cards": objects.NewRichMessage(objects.TemplateCards, objects.RichMessageElement{
+			Title: "title",
+			Buttons: []objects.RichMessageButton{
+				{Text: "Yes", Type: "message", Value: "yes", PostbackID: "yes"},
+				{Text: "Open", Type: "url", Value: "https://example.com", Target: "new"},
+			},
+		}),
+		"sticker": objects.NewRichMessage(objects.TemplateSticker, objects.RichMessageElement{
+			Image: &objects.RichMessageImage{URL: "https://cdn.livechatinc.com/sticker.png"},
+		}),
+	}
+
+	for name, e := range cases {
+		if err := objects.ValidateEvent(e); err != nil {
+			t.Errorf("Event %s should be valid: %v", name, err)
+		}
+	}
+}
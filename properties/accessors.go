@@ -0,0 +1,184 @@
+package properties
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/livechat/lc-sdk-go/v2/objects"
+)
+
+// value returns raw value of given property or its default value if the property is not set.
+func (s *Schema) value(props objects.Properties, name string, kind Kind) (interface{}, error) {
+	d, exists := s.definitions[name]
+	if !exists {
+		return nil, fmt.Errorf("property %s.%s not defined", s.namespace, name)
+	}
+	if d.Kind != kind {
+		return nil, fmt.Errorf("property %s.%s is of kind %v, not %v", s.namespace, name, d.Kind, kind)
+	}
+	if v, exists := props[s.namespace][name]; exists && v != nil {
+		return v, nil
+	}
+	return d.Default, nil
+}
+
+func (s *Schema) invalidValue(name string, v interface{}) error {
+	return fmt.Errorf("property %s.%s has invalid value: %v (%T)", s.namespace, name, v, v)
+}
+
+// Int returns value of given int property or its default value if the property is not set.
+func (s *Schema) Int(props objects.Properties, name string) (int, error) {
+	v, err := s.value(props, name, KindInt)
+	if err != nil {
+		return 0, err
+	}
+	switch i := v.(type) {
+	case int:
+		return i, nil
+	case int64:
+		return int(i), nil
+	case float64:
+		if i != math.Trunc(i) {
+			return 0, s.invalidValue(name, v)
+		}
+		return int(i), nil
+	case json.Number:
+		n, err := i.Int64()
+		if err != nil {
+			return 0, s.invalidValue(name, v)
+		}
+		return int(n), nil
+	}
+	return 0, s.invalidValue(name, v)
+}
+
+// String returns value of given string property or its default value if the property is not set.
+func (s *Schema) String(props objects.Properties, name string) (string, error) {
+	v, err := s.value(props, name, KindString)
+	if err != nil {
+		return "", err
+	}
+	str, ok := v.(string)
+	if !ok {
+		return "", s.invalidValue(name, v)
+	}
+	return str, nil
+}
+
+// Bool returns value of given bool property or its default value if the property is not set.
+func (s *Schema) Bool(props objects.Properties, name string) (bool, error) {
+	v, err := s.value(props, name, KindBool)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, s.invalidValue(name, v)
+	}
+	return b, nil
+}
+
+// Time returns value of given time property or its default value if the property is not set.
+func (s *Schema) Time(props objects.Properties, name string) (time.Time, error) {
+	v, err := s.value(props, name, KindTime)
+	if err != nil {
+		return time.Time{}, err
+	}
+	switch t := v.(type) {
+	case time.Time:
+		return t, nil
+	case string:
+		parsed, err := time.Parse(time.RFC3339Nano, t)
+		if err != nil {
+			return time.Time{}, s.invalidValue(name, v)
+		}
+		return parsed, nil
+	}
+	return time.Time{}, s.invalidValue(name, v)
+}
+
+// StringList returns value of given string list property or its default value if the property is not set.
+func (s *Schema) StringList(props objects.Properties, name string) ([]string, error) {
+	v, err := s.value(props, name, KindStringList)
+	if err != nil {
+		return nil, err
+	}
+	switch l := v.(type) {
+	case []string:
+		return l, nil
+	case string:
+		return strings.Fields(l), nil
+	case []interface{}:
+		list := make([]string, 0, len(l))
+		for _, el := range l {
+			str, ok := el.(string)
+			if !ok {
+				return nil, s.invalidValue(name, v)
+			}
+			list = append(list, str)
+		}
+		return list, nil
+	}
+	return nil, s.invalidValue(name, v)
+}
+
+// Set stores value of given property in props, encoded as expected by LiveChat APIs.
+//
+// Value has to match property kind, ie. int, string, bool, time.Time or []string. Props has to be initialized.
+func (s *Schema) Set(props objects.Properties, name string, value interface{}) error {
+	if props == nil {
+		return fmt.Errorf("cannot set property %s.%s in nil properties", s.namespace, name)
+	}
+	d, exists := s.definitions[name]
+	if !exists {
+		return fmt.Errorf("property %s.%s not defined", s.namespace, name)
+	}
+
+	var encoded interface{}
+	switch v := value.(type) {
+	case int:
+		if d.Kind == KindInt {
+			encoded = v
+		}
+	case string:
+		if d.Kind == KindString {
+			encoded = v
+		}
+	case bool:
+		if d.Kind == KindBool {
+			encoded = v
+		}
+	case time.Time:
+		if d.Kind == KindTime {
+			encoded = v.Format(time.RFC3339Nano)
+		}
+	case []string:
+		if d.Kind == KindStringList {
+			for _, token := range v {
+				if token == "" || strings.ContainsAny(token, " \t\n\r") {
+					return fmt.Errorf("property %s.%s cannot store token %q", s.namespace, name, token)
+				}
+			}
+			encoded = strings.Join(v, " ")
+		}
+	}
+	if encoded == nil {
+		return fmt.Errorf("property %s.%s of kind %v cannot store value of type %T", s.namespace, name, d.Kind, value)
+	}
+
+	if props[s.namespace] == nil {
+		props[s.namespace] = make(map[string]interface{})
+	}
+	props[s.namespace][name] = encoded
+	return nil
+}
+
+// Names returns given property names in form expected by Delete*Properties methods of chat APIs.
+func (s *Schema) Names(names ...string) map[string][]string {
+	return map[string][]string{
+		s.namespace: names,
+	}
+}
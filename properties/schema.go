@@ -0,0 +1,201 @@
+// Package properties provides typed access to LiveChat properties.
+//
+// Properties of a namespace are described with Schema, which allows to read and write
+// objects.Properties with typed accessors and to generate configuration.PropertyConfig
+// for registering properties via Configuration API.
+//
+// General LiveChat properties documentation is available here:
+// https://developers.livechatinc.com/docs/management/configuration-api/#properties
+package properties
+
+import (
+	"sort"
+	"time"
+
+	"github.com/livechat/lc-sdk-go/v2/configuration"
+)
+
+// Kind represents Go type of property value.
+type Kind int
+
+// Supported property kinds.
+const (
+	KindInt Kind = iota
+	KindString
+	KindBool
+	// KindTime values are stored as strings in RFC 3339 format.
+	KindTime
+	// KindStringList values are stored as whitespace separated tokens.
+	KindStringList
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindInt:
+		return "int"
+	case KindString:
+		return "string"
+	case KindBool:
+		return "bool"
+	case KindTime:
+		return "time"
+	case KindStringList:
+		return "string list"
+	}
+	return "unknown"
+}
+
+// PropertyType returns LiveChat property type used to store values of given kind.
+func (k Kind) PropertyType() string {
+	switch k {
+	case KindInt:
+		return "int"
+	case KindBool:
+		return "bool"
+	case KindStringList:
+		return "tokenized_string"
+	default:
+		return "string"
+	}
+}
+
+// Definition describes single property of a Schema.
+type Definition struct {
+	Name        string
+	Kind        Kind
+	Default     interface{}
+	Description string
+	Locations   map[string]*configuration.Location
+	Domain      []interface{}
+	Range       *configuration.PropertyRange
+}
+
+// WithDescription sets property's description.
+func (d *Definition) WithDescription(description string) *Definition {
+	d.Description = description
+	return d
+}
+
+// WithAccess grants given user type (ie. agent or customer) read and/or write access
+// to the property in given location (ie. chat, thread, event, license or group).
+func (d *Definition) WithAccess(location, userType string, read, write bool) *Definition {
+	if d.Locations == nil {
+		d.Locations = make(map[string]*configuration.Location)
+	}
+	loc, exists := d.Locations[location]
+	if !exists {
+		loc = &configuration.Location{Access: make(map[string]*configuration.PropertyAccess)}
+		d.Locations[location] = loc
+	}
+	loc.Access[userType] = &configuration.PropertyAccess{Read: read, Write: write}
+	return d
+}
+
+// WithDomain restricts property values to given ones.
+func (d *Definition) WithDomain(values ...interface{}) *Definition {
+	d.Domain = values
+	return d
+}
+
+// WithRange restricts values of int property to given range.
+func (d *Definition) WithRange(from, to int) *Definition {
+	d.Range = &configuration.PropertyRange{From: from, To: to}
+	return d
+}
+
+// PropertyConfig returns configuration of the property, as expected by Configuration API.
+func (d *Definition) PropertyConfig() *configuration.PropertyConfig {
+	return &configuration.PropertyConfig{
+		Type:        d.Kind.PropertyType(),
+		Locations:   d.Locations,
+		Description: d.Description,
+		Domain:      d.Domain,
+		Range:       d.Range,
+	}
+}
+
+// Schema describes properties of a single namespace.
+type Schema struct {
+	namespace   string
+	definitions map[string]*Definition
+}
+
+// NewSchema creates empty Schema for given namespace.
+//
+// Properties registered by an application are stored in namespace equal to application's client ID.
+func NewSchema(namespace string) *Schema {
+	return &Schema{
+		namespace:   namespace,
+		definitions: make(map[string]*Definition),
+	}
+}
+
+// Namespace returns namespace described by Schema.
+func (s *Schema) Namespace() string {
+	return s.namespace
+}
+
+func (s *Schema) define(name string, kind Kind, def interface{}) *Definition {
+	d := &Definition{
+		Name:    name,
+		Kind:    kind,
+		Default: def,
+	}
+	s.definitions[name] = d
+	return d
+}
+
+// DefineInt adds int property with given default value to Schema.
+func (s *Schema) DefineInt(name string, def int) *Definition {
+	return s.define(name, KindInt, def)
+}
+
+// DefineString adds string property with given default value to Schema.
+func (s *Schema) DefineString(name, def string) *Definition {
+	return s.define(name, KindString, def)
+}
+
+// DefineBool adds bool property with given default value to Schema.
+func (s *Schema) DefineBool(name string, def bool) *Definition {
+	return s.define(name, KindBool, def)
+}
+
+// DefineTime adds time property with given default value to Schema.
+func (s *Schema) DefineTime(name string, def time.Time) *Definition {
+	return s.define(name, KindTime, def)
+}
+
+// DefineStringList adds string list property with given default value to Schema.
+func (s *Schema) DefineStringList(name string, def []string) *Definition {
+	return s.define(name, KindStringList, def)
+}
+
+// Definition returns definition of given property or nil if property is not defined.
+func (s *Schema) Definition(name string) *Definition {
+	return s.definitions[name]
+}
+
+// Definitions returns all definitions of Schema sorted by property name.
+func (s *Schema) Definitions() []*Definition {
+	names := make([]string, 0, len(s.definitions))
+	for name := range s.definitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	defs := make([]*Definition, 0, len(names))
+	for _, name := range names {
+		defs = append(defs, s.definitions[name])
+	}
+	return defs
+}
+
+// PropertyConfigs returns configuration of all Schema properties, ready to be passed
+// to RegisterProperties method of Configuration API.
+func (s *Schema) PropertyConfigs() map[string]*configuration.PropertyConfig {
+	configs := make(map[string]*configuration.PropertyConfig, len(s.definitions))
+	for name, d := range s.definitions {
+		configs[name] = d.PropertyConfig()
+	}
+	return configs
+}
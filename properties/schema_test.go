@@ -0,0 +1,129 @@
+package properties_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/livechat/lc-sdk-go/v2/objects"
+	"github.com/livechat/lc-sdk-go/v2/properties"
+)
+
+func newTestSchema() *properties.Schema {
+	s := properties.NewSchema("app")
+	s.DefineInt("score", 5).WithRange(0, 10).WithAccess("chat", "agent", true, true)
+	s.DefineString("priority", "normal").WithDomain("low", "normal", "high").WithDescription("Chat priority")
+	s.DefineBool("vip", false)
+	s.DefineTime("callback_at", time.Time{})
+	s.DefineStringList("labels", nil).WithAccess("chat", "customer", true, false)
+	return s
+}
+
+func TestSchemaReturnsDefaultsForMissingProperties(t *testing.T) {
+	s := newTestSchema()
+	props := objects.Properties{}
+
+	if v, err := s.Int(props, "score"); v != 5 || err != nil {
+		t.Errorf("invalid score: %v, %v", v, err)
+	}
+	if v, err := s.String(props, "priority"); v != "normal" || err != nil {
+		t.Errorf("invalid priority: %v, %v", v, err)
+	}
+	if v, err := s.StringList(props, "labels"); len(v) != 0 || err != nil {
+		t.Errorf("invalid labels: %v, %v", v, err)
+	}
+}
+
+func TestSchemaDecodesPropertiesFromJSON(t *testing.T) {
+	s := newTestSchema()
+	var props objects.Properties
+	raw := `{"app": {"score": 7, "priority": "high", "vip": true, "callback_at": "2020-01-01T12:00:00Z", "labels": "refund urgent"}}`
+	if err := json.Unmarshal([]byte(raw), &props); err != nil {
+		t.Fatalf("couldn't unmarshal properties: %v", err)
+	}
+
+	if v, err := s.Int(props, "score"); v != 7 || err != nil {
+		t.Errorf("invalid score: %v, %v", v, err)
+	}
+	if v, err := s.String(props, "priority"); v != "high" || err != nil {
+		t.Errorf("invalid priority: %v, %v", v, err)
+	}
+	if v, err := s.Bool(props, "vip"); !v || err != nil {
+		t.Errorf("invalid vip: %v, %v", v, err)
+	}
+	if v, err := s.Time(props, "callback_at"); !v.Equal(time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)) || err != nil {
+		t.Errorf("invalid callback_at: %v, %v", v, err)
+	}
+	if v, err := s.StringList(props, "labels"); len(v) != 2 || v[1] != "urgent" || err != nil {
+		t.Errorf("invalid labels: %v, %v", v, err)
+	}
+}
+
+func TestSchemaRejectsInvalidAccess(t *testing.T) {
+	s := newTestSchema()
+	props := objects.Properties{"app": {"score": 7.5}}
+
+	if _, err := s.Int(props, "score"); err == nil {
+		t.Errorf("non-integer score should be rejected")
+	}
+	if _, err := s.String(props, "score"); err == nil {
+		t.Errorf("reading int property as string should be rejected")
+	}
+	if _, err := s.Int(props, "unknown"); err == nil {
+		t.Errorf("reading undefined property should be rejected")
+	}
+	if err := s.Set(props, "score", "7"); err == nil {
+		t.Errorf("setting string to int property should be rejected")
+	}
+	if err := s.Set(props, "labels", []string{"two words"}); err == nil {
+		t.Errorf("setting token with whitespace should be rejected")
+	}
+	if err := s.Set(nil, "score", 7); err == nil {
+		t.Errorf("setting property in nil properties should be rejected")
+	}
+}
+
+func TestSchemaSetRoundTrip(t *testing.T) {
+	s := newTestSchema()
+	props := objects.Properties{}
+	callbackAt := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	for name, value := range map[string]interface{}{
+		"score":       3,
+		"priority":    "low",
+		"vip":         true,
+		"callback_at": callbackAt,
+		"labels":      []string{"a", "b"},
+	} {
+		if err := s.Set(props, name, value); err != nil {
+			t.Errorf("Set %v failed: %v", name, err)
+		}
+	}
+
+	if props["app"]["labels"] != "a b" || props["app"]["callback_at"] != "2020-01-01T12:00:00Z" {
+		t.Errorf("invalid encoded properties: %v", props)
+	}
+	if v, err := s.Time(props, "callback_at"); !v.Equal(callbackAt) || err != nil {
+		t.Errorf("invalid callback_at: %v, %v", v, err)
+	}
+}
+
+func TestSchemaPropertyConfigs(t *testing.T) {
+	configs := newTestSchema().PropertyConfigs()
+
+	if len(configs) != 5 {
+		t.Fatalf("invalid configs count: %v", len(configs))
+	}
+	if c := configs["score"]; c.Type != "int" || c.Range.To != 10 || !c.Locations["chat"].Access["agent"].Write {
+		t.Errorf("invalid score config: %v", c)
+	}
+	if c := configs["priority"]; c.Type != "string" || len(c.Domain) != 3 || c.Description != "Chat priority" {
+		t.Errorf("invalid priority config: %v", c)
+	}
+	if c := configs["callback_at"]; c.Type != "string" {
+		t.Errorf("invalid callback_at config: %v", c)
+	}
+	if c := configs["labels"]; c.Type != "tokenized_string" || c.Locations["chat"].Access["customer"].Write {
+		t.Errorf("invalid labels config: %v", c)
+	}
+}
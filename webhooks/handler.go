@@ -87,89 +87,109 @@ func (cfg *Configuration) WithErrorHandler(h ErrorHandler) *Configuration {
 // those structures into webhook Handlers attached to given webhook type.
 func NewWebhookHandler(cfg *Configuration) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		body, err := ioutil.ReadAll(r.Body)
-		if err != nil {
-			cfg.handleError(w, fmt.Sprintf("couldn't read request body: %v", err), http.StatusInternalServerError)
+		wh, ok := readWebhook(w, r, cfg.handleError)
+		if !ok {
 			return
 		}
+		cfg.dispatch(w, r, wh)
+	}
+}
 
-		var wh Webhook
-		if err := json.Unmarshal(body, &wh); err != nil {
-			cfg.handleError(w, fmt.Sprintf("couldn't unmarshal webhook base: %v", err), http.StatusInternalServerError)
-			return
-		}
-		acfg, exists := cfg.actions[wh.Action]
-		if !exists {
-			cfg.handleError(w, fmt.Sprintf("Unsupported action: %v", wh.Action), http.StatusBadRequest)
-			return
-		}
-		if acfg.secretKey != "" && wh.SecretKey != acfg.secretKey {
-			cfg.handleError(w, "Invalid webhook secret key", http.StatusBadRequest)
-			return
-		}
+func readWebhook(w http.ResponseWriter, r *http.Request, handleError ErrorHandler) (*Webhook, bool) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		handleError(w, fmt.Sprintf("couldn't read request body: %v", err), http.StatusInternalServerError)
+		return nil, false
+	}
 
-		var payload interface{}
-		switch wh.Action {
-		case "incoming_chat":
-			payload = &IncomingChat{}
-		case "incoming_event":
-			payload = &IncomingEvent{}
-		case "event_updated":
-			payload = &EventUpdated{}
-		case "incoming_rich_message_postback":
-			payload = &IncomingRichMessagePostback{}
-		case "chat_deactivated":
-			payload = &ChatDeactivated{}
-		case "chat_properties_updated":
-			payload = &ChatPropertiesUpdated{}
-		case "thread_properties_updated":
-			payload = &ThreadPropertiesUpdated{}
-		case "chat_properties_deleted":
-			payload = &ChatPropertiesDeleted{}
-		case "thread_properties_deleted":
-			payload = &ThreadPropertiesDeleted{}
-		case "chat_user_added":
-			payload = &ChatUserAdded{}
-		case "chat_user_removed":
-			payload = &ChatUserRemoved{}
-		case "thread_tagged":
-			payload = &ThreadTagged{}
-		case "thread_untagged":
-			payload = &ThreadUntagged{}
-		case "agent_deleted":
-			payload = &AgentDeleted{}
-		case "events_marked_as_seen":
-			payload = &EventsMarkedAsSeen{}
-		case "access_granted":
-			payload = &AccessGranted{}
-		case "access_revoked":
-			payload = &AccessRevoked{}
-		case "access_set":
-			payload = &AccessSet{}
-		case "customer_created":
-			payload = &CustomerCreated{}
-		case "event_properties_updated":
-			payload = &EventPropertiesUpdated{}
-		case "event_properties_deleted":
-			payload = &EventPropertiesDeleted{}
-		case "routing_status_set":
-			payload = &RoutingStatusSet{}
-		default:
-			cfg.handleError(w, fmt.Sprintf("unknown webhook: %v", wh.Action), http.StatusBadRequest)
-			return
-		}
+	var wh Webhook
+	if err := json.Unmarshal(body, &wh); err != nil {
+		handleError(w, fmt.Sprintf("couldn't unmarshal webhook base: %v", err), http.StatusInternalServerError)
+		return nil, false
+	}
+	return &wh, true
+}
 
-		if err := json.Unmarshal(wh.RawPayload, payload); err != nil {
-			cfg.handleError(w, fmt.Sprintf("couldn't unmarshal webhook payload: %v", err), http.StatusInternalServerError)
-			return
-		}
-		wh.Payload = payload
+func (cfg *Configuration) dispatch(w http.ResponseWriter, r *http.Request, wh *Webhook) {
+	acfg, exists := cfg.actions[wh.Action]
+	if !exists {
+		cfg.handleError(w, fmt.Sprintf("Unsupported action: %v", wh.Action), http.StatusBadRequest)
+		return
+	}
+	if acfg.secretKey != "" && wh.SecretKey != acfg.secretKey {
+		cfg.handleError(w, "Invalid webhook secret key", http.StatusBadRequest)
+		return
+	}
 
-		if err = acfg.handle(r.Context(), &wh); err != nil {
-			cfg.handleError(w, fmt.Sprintf("webhook handler error: %v", err), http.StatusInternalServerError)
-			return
-		}
+	payload := newPayload(wh.Action)
+	if payload == nil {
+		cfg.handleError(w, fmt.Sprintf("unknown webhook: %v", wh.Action), http.StatusBadRequest)
+		return
+	}
+
+	if err := json.Unmarshal(wh.RawPayload, payload); err != nil {
+		cfg.handleError(w, fmt.Sprintf("couldn't unmarshal webhook payload: %v", err), http.StatusInternalServerError)
+		return
+	}
+	wh.Payload = payload
+
+	if err := acfg.handle(r.Context(), wh); err != nil {
+		cfg.handleError(w, fmt.Sprintf("webhook handler error: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
 
-		w.WriteHeader(http.StatusOK)
+// newPayload returns pointer to empty payload structure for given webhook action
+// or nil if the action is not known.
+func newPayload(action string) interface{} {
+	switch action {
+	case "incoming_chat":
+		return &IncomingChat{}
+	case "incoming_event":
+		return &IncomingEvent{}
+	case "event_updated":
+		return &EventUpdated{}
+	case "incoming_rich_message_postback":
+		return &IncomingRichMessagePostback{}
+	case "chat_deactivated":
+		return &ChatDeactivated{}
+	case "chat_properties_updated":
+		return &ChatPropertiesUpdated{}
+	case "thread_properties_updated":
+		return &ThreadPropertiesUpdated{}
+	case "chat_properties_deleted":
+		return &ChatPropertiesDeleted{}
+	case "thread_properties_deleted":
+		return &ThreadPropertiesDeleted{}
+	case "chat_user_added":
+		return &ChatUserAdded{}
+	case "chat_user_removed":
+		return &ChatUserRemoved{}
+	case "thread_tagged":
+		return &ThreadTagged{}
+	case "thread_untagged":
+		return &ThreadUntagged{}
+	case "agent_deleted":
+		return &AgentDeleted{}
+	case "events_marked_as_seen":
+		return &EventsMarkedAsSeen{}
+	case "access_granted":
+		return &AccessGranted{}
+	case "access_revoked":
+		return &AccessRevoked{}
+	case "access_set":
+		return &AccessSet{}
+	case "customer_created":
+		return &CustomerCreated{}
+	case "event_properties_updated":
+		return &EventPropertiesUpdated{}
+	case "event_properties_deleted":
+		return &EventPropertiesDeleted{}
+	case "routing_status_set":
+		return &RoutingStatusSet{}
+	default:
+		return nil
 	}
 }
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/livechat/lc-sdk-go/v2/objects"
 	"github.com/livechat/lc-sdk-go/v2/webhooks"
 )
 
@@ -77,7 +78,7 @@ func incomingChat(wh *webhooks.Webhook) error {
 	propEq("Agent.Avatar", agent.Avatar, "livechat.s3.amazonaws.com/default/avatars/a14.png", &errors)
 	propEq("Agent.Present", agent.Present, true, &errors)
 	propEq("Agent.EventsSeenUpTo", agent.EventsSeenUpTo.String(), "1970-01-01 01:00:00 +0000 UTC", &errors)
-	propEq("Agent.RoutingStatus", agent.RoutingStatus, "accepting_chats", &errors)
+	propEq("Agent.RoutingStatus", agent.RoutingStatus, objects.AcceptingChats, &errors)
 
 	propEq("Chat.Threads.length", len(chat.Threads), 1, &errors)
 	thread := chat.Threads[0]
@@ -531,7 +532,7 @@ func routingStatusSet(wh *webhooks.Webhook) error {
 
 	var errors string
 	propEq("AgentID", payload.AgentID, "5c9871d5372c824cbf22d860a707a578", &errors)
-	propEq("Status", payload.Status, "accepting chats", &errors)
+	propEq("Status", payload.Status, objects.AcceptingChats, &errors)
 
 	if errors != "" {
 		return fmt.Errorf(errors)
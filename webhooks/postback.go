@@ -0,0 +1,83 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// The PostbackHandler type is used to define processors of rich message postbacks.
+//
+// It is called by PostbackRouter with decoded payload of incoming_rich_message_postback webhook.
+type PostbackHandler func(ctx context.Context, wh *Webhook, postback *IncomingRichMessagePostback) error
+
+type prefixRoute struct {
+	prefix string
+	handle PostbackHandler
+}
+
+// PostbackRouter dispatches incoming_rich_message_postback webhooks to PostbackHandlers based on postback ID.
+//
+// Handlers registered for exact postback ID take precedence over those registered for postback ID prefix.
+// Prefix handlers are matched in order of registration.
+type PostbackRouter struct {
+	routes         map[string]PostbackHandler
+	prefixRoutes   []prefixRoute
+	defaultHandler PostbackHandler
+}
+
+// NewPostbackRouter creates PostbackRouter with no handlers attached.
+func NewPostbackRouter() *PostbackRouter {
+	return &PostbackRouter{
+		routes: make(map[string]PostbackHandler),
+	}
+}
+
+// Handle attaches PostbackHandler for given postback ID.
+func (pr *PostbackRouter) Handle(postbackID string, h PostbackHandler) *PostbackRouter {
+	pr.routes[postbackID] = h
+	return pr
+}
+
+// HandlePrefix attaches PostbackHandler for all postback IDs starting with given prefix.
+func (pr *PostbackRouter) HandlePrefix(prefix string, h PostbackHandler) *PostbackRouter {
+	pr.prefixRoutes = append(pr.prefixRoutes, prefixRoute{prefix, h})
+	return pr
+}
+
+// HandleDefault attaches PostbackHandler for postbacks not matched by any other handler.
+//
+// If no default handler is attached, then unmatched postbacks result in an error.
+func (pr *PostbackRouter) HandleDefault(h PostbackHandler) *PostbackRouter {
+	pr.defaultHandler = h
+	return pr
+}
+
+// HandleWebhook passes incoming_rich_message_postback webhook to matching PostbackHandler.
+// It can be used as HandlerContext.
+func (pr *PostbackRouter) HandleWebhook(ctx context.Context, wh *Webhook) error {
+	postback, ok := wh.Payload.(*IncomingRichMessagePostback)
+	if !ok {
+		return fmt.Errorf("invalid payload type: %T", wh.Payload)
+	}
+
+	if h, exists := pr.routes[postback.Postback.ID]; exists {
+		return h(ctx, wh, postback)
+	}
+	for _, r := range pr.prefixRoutes {
+		if strings.HasPrefix(postback.Postback.ID, r.prefix) {
+			return r.handle(ctx, wh, postback)
+		}
+	}
+	if pr.defaultHandler != nil {
+		return pr.defaultHandler(ctx, wh, postback)
+	}
+	return fmt.Errorf("no handler for postback: %v", postback.Postback.ID)
+}
+
+// WithPostbackRouter attaches PostbackRouter as a handler of incoming_rich_message_postback webhooks.
+//
+// See WithAction for details of secretKey validation.
+func (cfg *Configuration) WithPostbackRouter(pr *PostbackRouter, secretKey string) *Configuration {
+	return cfg.WithActionContext("incoming_rich_message_postback", pr.HandleWebhook, secretKey)
+}
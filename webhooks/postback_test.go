@@ -0,0 +1,79 @@
+package webhooks_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/livechat/lc-sdk-go/v2/webhooks"
+)
+
+func sendPostback(t *testing.T, pr *webhooks.PostbackRouter) *httptest.ResponseRecorder {
+	cfg := webhooks.NewConfiguration().WithPostbackRouter(pr, "dummy_key")
+	h := webhooks.NewWebhookHandler(cfg)
+	payload, err := ioutil.ReadFile("./testdata/incoming_rich_message_postback.json")
+	if err != nil {
+		t.Fatalf("Missing test payload for action incoming_rich_message_postback")
+	}
+	req := httptest.NewRequest("POST", "https://example.com", bytes.NewBuffer(payload))
+	resp := httptest.NewRecorder()
+	h(resp, req)
+	return resp
+}
+
+func TestPostbackRouterMatchesExactPostbackID(t *testing.T) {
+	var matched string
+	pr := webhooks.NewPostbackRouter().
+		HandlePrefix("action_", func(context.Context, *webhooks.Webhook, *webhooks.IncomingRichMessagePostback) error {
+			matched = "prefix"
+			return nil
+		}).
+		Handle("action_yes", func(ctx context.Context, wh *webhooks.Webhook, p *webhooks.IncomingRichMessagePostback) error {
+			matched = "exact"
+			if p.ChatID != "PJ0MRSHTDG" || !p.Postback.Toggled {
+				t.Errorf("invalid postback payload: %v", p)
+			}
+			return nil
+		})
+
+	resp := sendPostback(t, pr)
+	if resp.Code != http.StatusOK {
+		t.Errorf("invalid code: %v", resp.Code)
+	}
+	if matched != "exact" {
+		t.Errorf("invalid handler matched: %v", matched)
+	}
+}
+
+func TestPostbackRouterFallsBackToPrefixAndDefault(t *testing.T) {
+	var matched string
+	pr := webhooks.NewPostbackRouter().
+		HandlePrefix("action_", func(context.Context, *webhooks.Webhook, *webhooks.IncomingRichMessagePostback) error {
+			matched = "prefix"
+			return nil
+		})
+	sendPostback(t, pr)
+	if matched != "prefix" {
+		t.Errorf("invalid handler matched: %v", matched)
+	}
+
+	pr = webhooks.NewPostbackRouter().
+		HandleDefault(func(context.Context, *webhooks.Webhook, *webhooks.IncomingRichMessagePostback) error {
+			matched = "default"
+			return nil
+		})
+	sendPostback(t, pr)
+	if matched != "default" {
+		t.Errorf("invalid handler matched: %v", matched)
+	}
+}
+
+func TestPostbackRouterRejectsUnmatchedPostback(t *testing.T) {
+	resp := sendPostback(t, webhooks.NewPostbackRouter())
+	if resp.Code != http.StatusInternalServerError {
+		t.Errorf("invalid code: %v", resp.Code)
+	}
+}
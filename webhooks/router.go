@@ -0,0 +1,153 @@
+package webhooks
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// License represents webhooks configuration of a single license (ie. single app installation).
+type License struct {
+	// SecretKey is a secret of webhooks registered for the license.
+	// If it is an empty string, then no license-level validation of webhook's secret is performed.
+	SecretKey string
+	// Configuration defines handlers for webhooks incoming from the license.
+	Configuration *Configuration
+}
+
+// The LicenseLookup interface is used by Router to resolve webhooks configuration for given license.
+//
+// Lookup should return nil License (and nil error) if the app is not installed on given license.
+type LicenseLookup interface {
+	Lookup(licenseID int) (*License, error)
+}
+
+// The LicenseLookupFunc type is an adapter that allows to use ordinary functions as LicenseLookup.
+type LicenseLookupFunc func(licenseID int) (*License, error)
+
+// Lookup calls f(licenseID).
+func (f LicenseLookupFunc) Lookup(licenseID int) (*License, error) {
+	return f(licenseID)
+}
+
+// LicenseStore is in-memory, concurrency safe LicenseLookup implementation.
+type LicenseStore struct {
+	licenses map[int]*License
+	mu       sync.RWMutex
+}
+
+// NewLicenseStore creates empty LicenseStore.
+func NewLicenseStore() *LicenseStore {
+	return &LicenseStore{
+		licenses: make(map[int]*License),
+	}
+}
+
+// Install stores webhooks configuration for given license.
+func (s *LicenseStore) Install(licenseID int, license *License) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.licenses[licenseID] = license
+}
+
+// Uninstall removes webhooks configuration for given license.
+func (s *LicenseStore) Uninstall(licenseID int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.licenses, licenseID)
+}
+
+// Lookup returns webhooks configuration stored for given license.
+func (s *LicenseStore) Lookup(licenseID int) (*License, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.licenses[licenseID], nil
+}
+
+// Router is an http.Handler that dispatches webhooks incoming from multiple licenses.
+//
+// For each webhook, Router resolves License by webhook's LicenseID, validates its secret key
+// and passes the webhook to handlers defined in license's Configuration. Webhooks from licenses
+// that are not installed or are disabled are rejected.
+type Router struct {
+	lookup      LicenseLookup
+	disabled    map[int]bool
+	mu          sync.RWMutex
+	handleError ErrorHandler
+}
+
+// NewRouter creates Router that resolves licenses with given LicenseLookup and uses
+// http.Error to handle webhook processing errors.
+func NewRouter(lookup LicenseLookup) *Router {
+	return &Router{
+		lookup:      lookup,
+		disabled:    make(map[int]bool),
+		handleError: http.Error,
+	}
+}
+
+// WithErrorHandler allows to attach custom ErrorHandler for errors that occur before webhook
+// is passed to license's Configuration.
+//
+// Errors that occur later are handled by ErrorHandler of license's Configuration.
+func (rt *Router) WithErrorHandler(h ErrorHandler) *Router {
+	rt.handleError = h
+	return rt
+}
+
+// EnableLicense resumes processing of webhooks incoming from given license.
+func (rt *Router) EnableLicense(licenseID int) {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+
+	delete(rt.disabled, licenseID)
+}
+
+// DisableLicense stops processing of webhooks incoming from given license.
+// Such webhooks are rejected until license is enabled again.
+func (rt *Router) DisableLicense(licenseID int) {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+
+	rt.disabled[licenseID] = true
+}
+
+// IsLicenseEnabled returns information whether webhooks incoming from given license are processed.
+func (rt *Router) IsLicenseEnabled(licenseID int) bool {
+	rt.mu.RLock()
+	defer rt.mu.RUnlock()
+
+	return !rt.disabled[licenseID]
+}
+
+// ServeHTTP implements http.Handler interface for Router.
+func (rt *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	wh, ok := readWebhook(w, r, rt.handleError)
+	if !ok {
+		return
+	}
+
+	if !rt.IsLicenseEnabled(wh.LicenseID) {
+		rt.handleError(w, fmt.Sprintf("License disabled: %v", wh.LicenseID), http.StatusForbidden)
+		return
+	}
+
+	license, err := rt.lookup.Lookup(wh.LicenseID)
+	if err != nil {
+		rt.handleError(w, fmt.Sprintf("couldn't look up license %v: %v", wh.LicenseID, err), http.StatusInternalServerError)
+		return
+	}
+	if license == nil || license.Configuration == nil {
+		rt.handleError(w, fmt.Sprintf("License not installed: %v", wh.LicenseID), http.StatusForbidden)
+		return
+	}
+	if license.SecretKey != "" && wh.SecretKey != license.SecretKey {
+		rt.handleError(w, "Invalid webhook secret key", http.StatusBadRequest)
+		return
+	}
+
+	license.Configuration.dispatch(w, r, wh)
+}
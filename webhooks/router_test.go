@@ -0,0 +1,102 @@
+package webhooks_test
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/livechat/lc-sdk-go/v2/webhooks"
+)
+
+func sendToRouter(t *testing.T, rt *webhooks.Router, action string) *httptest.ResponseRecorder {
+	payload, err := ioutil.ReadFile("./testdata/" + action + ".json")
+	if err != nil {
+		t.Fatalf("Missing test payload for action %v", action)
+	}
+	req := httptest.NewRequest("POST", "https://example.com", bytes.NewBuffer(payload))
+	resp := httptest.NewRecorder()
+	rt.ServeHTTP(resp, req)
+	return resp
+}
+
+func TestRouterDispatchesWebhookToLicenseConfiguration(t *testing.T) {
+	handled := false
+	store := webhooks.NewLicenseStore()
+	store.Install(21377312, &webhooks.License{
+		SecretKey: "dummy_key",
+		Configuration: webhooks.NewConfiguration().WithAction("incoming_chat", func(wh *webhooks.Webhook) error {
+			handled = true
+			return incomingChat(wh)
+		}, ""),
+	})
+	rt := webhooks.NewRouter(store)
+
+	resp := sendToRouter(t, rt, "incoming_chat")
+	if resp.Code != http.StatusOK {
+		t.Errorf("invalid code: %v, body: %v", resp.Code, resp.Body)
+	}
+	if !handled {
+		t.Errorf("webhook not passed to license handler")
+	}
+}
+
+func TestRouterRejectsWebhooksFromNotInstalledLicense(t *testing.T) {
+	rt := webhooks.NewRouter(webhooks.NewLicenseStore())
+
+	resp := sendToRouter(t, rt, "incoming_chat")
+	if resp.Code != http.StatusForbidden {
+		t.Errorf("invalid code: %v", resp.Code)
+	}
+}
+
+func TestRouterRejectsWebhooksIfLicenseSecretKeyDoesntMatch(t *testing.T) {
+	store := webhooks.NewLicenseStore()
+	store.Install(21377312, &webhooks.License{
+		SecretKey:     "other_dummy_key",
+		Configuration: webhooks.NewConfiguration().WithAction("incoming_chat", incomingChat, ""),
+	})
+	rt := webhooks.NewRouter(store)
+
+	resp := sendToRouter(t, rt, "incoming_chat")
+	if resp.Code != http.StatusBadRequest {
+		t.Errorf("invalid code: %v", resp.Code)
+	}
+}
+
+func TestRouterRejectsWebhooksFromDisabledLicense(t *testing.T) {
+	store := webhooks.NewLicenseStore()
+	store.Install(21377312, &webhooks.License{
+		Configuration: webhooks.NewConfiguration().WithAction("incoming_chat", incomingChat, ""),
+	})
+	rt := webhooks.NewRouter(store)
+
+	rt.DisableLicense(21377312)
+	if rt.IsLicenseEnabled(21377312) {
+		t.Errorf("license should be disabled")
+	}
+	resp := sendToRouter(t, rt, "incoming_chat")
+	if resp.Code != http.StatusForbidden {
+		t.Errorf("invalid code: %v", resp.Code)
+	}
+
+	rt.EnableLicense(21377312)
+	resp = sendToRouter(t, rt, "incoming_chat")
+	if resp.Code != http.StatusOK {
+		t.Errorf("invalid code: %v, body: %v", resp.Code, resp.Body)
+	}
+}
+
+func TestRouterHandlesLookupErrors(t *testing.T) {
+	lookup := webhooks.LicenseLookupFunc(func(licenseID int) (*webhooks.License, error) {
+		return nil, errors.New("storage unavailable")
+	})
+	rt := webhooks.NewRouter(lookup)
+
+	resp := sendToRouter(t, rt, "incoming_chat")
+	if resp.Code != http.StatusInternalServerError {
+		t.Errorf("invalid code: %v", resp.Code)
+	}
+}
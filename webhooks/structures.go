@@ -169,8 +169,8 @@ type EventPropertiesDeleted struct {
 
 // RoutingStatusSet represents payload of routing_status_set webhook.
 type RoutingStatusSet struct {
-	AgentID string `json:"agent_id"`
-	Status  string `json:"status"`
+	AgentID string                `json:"agent_id"`
+	Status  objects.RoutingStatus `json:"status"`
 }
 
 // UnmarshalJSON implements json.Unmarshaler interface for IncomingChat.
@@ -0,0 +1,175 @@
+package webhooktest
+
+import (
+	"time"
+
+	"github.com/livechat/lc-sdk-go/v2/objects"
+	"github.com/livechat/lc-sdk-go/v2/webhooks"
+)
+
+const (
+	sampleChatID     = "PJ0MRSHTDG"
+	sampleThreadID   = "K600PKZON8"
+	sampleEventID    = "K600PKZON8_1"
+	sampleAgentID    = "agent@example.com"
+	sampleCustomerID = "b7eff798-f8df-4364-8059-649c35c9ed0c"
+)
+
+var sampleTime = time.Date(2020, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+func sampleEvent() objects.Event {
+	e, _ := EventFrom(&objects.Message{
+		Event: objects.Event{
+			ID:         sampleEventID,
+			CreatedAt:  sampleTime,
+			AuthorID:   sampleCustomerID,
+			Recipients: "all",
+			Type:       "message",
+		},
+		Text: "Hello",
+	})
+	return e
+}
+
+func sampleCustomer() objects.Customer {
+	c := objects.Customer{
+		User: &objects.User{
+			ID:      sampleCustomerID,
+			Type:    "customer",
+			Name:    "John Doe",
+			Email:   "john.doe@example.com",
+			Present: true,
+		},
+		CreatedAt: sampleTime,
+	}
+	c.LastVisit.StartedAt = sampleTime
+	return c
+}
+
+func sampleChat() objects.Chat {
+	customer := sampleCustomer()
+	return objects.Chat{
+		ID:     sampleChatID,
+		Access: objects.Access{GroupIDs: []int{0}},
+		Thread: objects.Thread{
+			ID:        sampleThreadID,
+			Active:    true,
+			UserIDs:   []string{sampleCustomerID, sampleAgentID},
+			Access:    objects.Access{GroupIDs: []int{0}},
+			Events:    []*objects.Event{},
+			CreatedAt: sampleTime,
+		},
+		Agents: map[string]*objects.Agent{
+			sampleAgentID: {
+				User: &objects.User{
+					ID:      sampleAgentID,
+					Type:    "agent",
+					Name:    "Agent Smith",
+					Email:   sampleAgentID,
+					Present: true,
+				},
+				RoutingStatus: "accepting_chats",
+			},
+		},
+		Customers: map[string]*objects.Customer{
+			sampleCustomerID: &customer,
+		},
+	}
+}
+
+func sampleProperties() objects.Properties {
+	return objects.Properties{
+		"routing": {
+			"priority": "high",
+		},
+	}
+}
+
+func sampleDeletedProperties() map[string][]string {
+	return map[string][]string{
+		"routing": {"priority"},
+	}
+}
+
+var samples = map[string]func() interface{}{
+	"incoming_chat": func() interface{} {
+		return &webhooks.IncomingChat{Chat: sampleChat()}
+	},
+	"incoming_event": func() interface{} {
+		return &webhooks.IncomingEvent{ChatID: sampleChatID, ThreadID: sampleThreadID, Event: sampleEvent()}
+	},
+	"event_updated": func() interface{} {
+		return &webhooks.EventUpdated{ChatID: sampleChatID, ThreadID: sampleThreadID, Event: sampleEvent()}
+	},
+	"incoming_rich_message_postback": func() interface{} {
+		p := &webhooks.IncomingRichMessagePostback{
+			UserID:   sampleCustomerID,
+			ChatID:   sampleChatID,
+			ThreadID: sampleThreadID,
+			EventID:  sampleEventID,
+		}
+		p.Postback.ID = "action_yes"
+		p.Postback.Toggled = true
+		return p
+	},
+	"chat_deactivated": func() interface{} {
+		return &webhooks.ChatDeactivated{ChatID: sampleChatID, ThreadID: sampleThreadID, UserID: sampleAgentID}
+	},
+	"chat_properties_updated": func() interface{} {
+		return &webhooks.ChatPropertiesUpdated{ChatID: sampleChatID, Properties: sampleProperties()}
+	},
+	"thread_properties_updated": func() interface{} {
+		return &webhooks.ThreadPropertiesUpdated{ChatID: sampleChatID, ThreadID: sampleThreadID, Properties: sampleProperties()}
+	},
+	"chat_properties_deleted": func() interface{} {
+		return &webhooks.ChatPropertiesDeleted{ChatID: sampleChatID, Properties: sampleDeletedProperties()}
+	},
+	"thread_properties_deleted": func() interface{} {
+		return &webhooks.ThreadPropertiesDeleted{ChatID: sampleChatID, ThreadID: sampleThreadID, Properties: sampleDeletedProperties()}
+	},
+	"chat_user_added": func() interface{} {
+		return &webhooks.ChatUserAdded{
+			ChatID:   sampleChatID,
+			ThreadID: sampleThreadID,
+			User:     *sampleChat().Agents[sampleAgentID].User,
+			UserType: "agent",
+		}
+	},
+	"chat_user_removed": func() interface{} {
+		return &webhooks.ChatUserRemoved{ChatID: sampleChatID, ThreadID: sampleThreadID, UserID: sampleAgentID, UserType: "agent"}
+	},
+	"thread_tagged": func() interface{} {
+		return &webhooks.ThreadTagged{ChatID: sampleChatID, ThreadID: sampleThreadID, Tag: "sales"}
+	},
+	"thread_untagged": func() interface{} {
+		return &webhooks.ThreadUntagged{ChatID: sampleChatID, ThreadID: sampleThreadID, Tag: "sales"}
+	},
+	"agent_deleted": func() interface{} {
+		return &webhooks.AgentDeleted{AgentID: sampleAgentID}
+	},
+	"events_marked_as_seen": func() interface{} {
+		return &webhooks.EventsMarkedAsSeen{UserID: sampleCustomerID, ChatID: sampleChatID, SeenUpTo: sampleTime.Format(time.RFC3339Nano)}
+	},
+	"access_granted": func() interface{} {
+		return &webhooks.AccessGranted{Resource: "chat", ID: sampleChatID, Access: objects.Access{GroupIDs: []int{1}}}
+	},
+	"access_revoked": func() interface{} {
+		return &webhooks.AccessRevoked{Resource: "chat", ID: sampleChatID, Access: objects.Access{GroupIDs: []int{1}}}
+	},
+	"access_set": func() interface{} {
+		return &webhooks.AccessSet{Resource: "chat", ID: sampleChatID, Access: objects.Access{GroupIDs: []int{0, 1}}}
+	},
+	"customer_created": func() interface{} {
+		c := webhooks.CustomerCreated(sampleCustomer())
+		return &c
+	},
+	"event_properties_updated": func() interface{} {
+		return &webhooks.EventPropertiesUpdated{ChatID: sampleChatID, ThreadID: sampleThreadID, EventID: sampleEventID, Properties: sampleProperties()}
+	},
+	"event_properties_deleted": func() interface{} {
+		return &webhooks.EventPropertiesDeleted{ChatID: sampleChatID, ThreadID: sampleThreadID, EventID: sampleEventID, Properties: sampleDeletedProperties()}
+	},
+	"routing_status_set": func() interface{} {
+		return &webhooks.RoutingStatusSet{AgentID: sampleAgentID, Status: "accepting_chats"}
+	},
+}
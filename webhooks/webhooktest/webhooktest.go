@@ -0,0 +1,245 @@
+// Package webhooktest provides utilities for testing webhook handlers built on top of webhooks package.
+//
+// It allows to build valid webhook envelopes for every supported webhook action from Go structures,
+// sign them with a secret key, send them to http.Handler and verify handler's response.
+package webhooktest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+
+	"github.com/livechat/lc-sdk-go/v2/objects"
+	"github.com/livechat/lc-sdk-go/v2/webhooks"
+)
+
+// DefaultWebhookID is used as webhook ID of all webhooks built by this package.
+const DefaultWebhookID = "webhooktest"
+
+// Envelope returns raw JSON of webhook with given action, license ID and secret key.
+//
+// Payload has to be one of webhooks payload structures (or a pointer to it) matching given action,
+// eg. webhooks.IncomingEvent for incoming_event action.
+func Envelope(action string, licenseID int, secretKey string, payload interface{}) ([]byte, error) {
+	sample, exists := samples[action]
+	if !exists {
+		return nil, fmt.Errorf("unsupported action: %v", action)
+	}
+
+	pt := reflect.TypeOf(payload)
+	if pt != nil && pt.Kind() == reflect.Ptr {
+		pt = pt.Elem()
+	}
+	if expected := reflect.TypeOf(sample()).Elem(); pt != expected {
+		return nil, fmt.Errorf("invalid payload type for action %v: %v, expected: %v", action, pt, expected)
+	}
+
+	rawPayload, err := marshalPayload(payload)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't marshal webhook payload: %v", err)
+	}
+
+	return json.Marshal(&webhooks.Webhook{
+		WebhookID:      DefaultWebhookID,
+		SecretKey:      secretKey,
+		Action:         action,
+		LicenseID:      licenseID,
+		AdditionalData: json.RawMessage("{}"),
+		RawPayload:     rawPayload,
+	})
+}
+
+// marshalPayload encodes payload the way LiveChat sends it. Chats are sent with a single thread and a list of
+// users, which objects.Chat splits into Agents and Customers, so incoming_chat payload is encoded explicitly.
+func marshalPayload(payload interface{}) ([]byte, error) {
+	switch p := payload.(type) {
+	case webhooks.IncomingChat:
+		return marshalIncomingChat(p)
+	case *webhooks.IncomingChat:
+		if p != nil {
+			return marshalIncomingChat(*p)
+		}
+	}
+	return json.Marshal(payload)
+}
+
+type wireChat struct {
+	ID         string             `json:"id,omitempty"`
+	Properties objects.Properties `json:"properties,omitempty"`
+	Access     objects.Access     `json:"access,omitempty"`
+	Thread     objects.Thread     `json:"thread"`
+	IsFollowed bool               `json:"is_followed,omitempty"`
+	Users      []interface{}      `json:"users"`
+}
+
+// marshalIncomingChat encodes IncomingChat with the last of Chat.Threads if Chat.Thread is empty and with users
+// sorted by ID, agents first.
+func marshalIncomingChat(p webhooks.IncomingChat) ([]byte, error) {
+	chat := p.Chat
+	thread := chat.Thread
+	if thread.ID == "" && len(chat.Threads) > 0 {
+		thread = chat.Threads[len(chat.Threads)-1]
+	}
+
+	agentIDs := make([]string, 0, len(chat.Agents))
+	for id := range chat.Agents {
+		agentIDs = append(agentIDs, id)
+	}
+	sort.Strings(agentIDs)
+	customerIDs := make([]string, 0, len(chat.Customers))
+	for id := range chat.Customers {
+		customerIDs = append(customerIDs, id)
+	}
+	sort.Strings(customerIDs)
+
+	users := make([]interface{}, 0, len(agentIDs)+len(customerIDs))
+	for _, id := range agentIDs {
+		users = append(users, chat.Agents[id])
+	}
+	for _, id := range customerIDs {
+		users = append(users, chat.Customers[id])
+	}
+
+	return json.Marshal(struct {
+		Chat wireChat `json:"chat"`
+	}{wireChat{
+		ID:         chat.ID,
+		Properties: chat.Properties,
+		Access:     chat.Access,
+		Thread:     thread,
+		IsFollowed: chat.IsFollowed,
+		Users:      users,
+	}})
+}
+
+// Actions returns sorted list of all webhook actions supported by this package.
+func Actions() []string {
+	actions := make([]string, 0, len(samples))
+	for action := range samples {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+	return actions
+}
+
+// SamplePayload returns pointer to valid, filled in payload structure for given action
+// or nil if given action is not supported.
+//
+// Each call returns new value, so it can be freely modified.
+func SamplePayload(action string) interface{} {
+	sample, exists := samples[action]
+	if !exists {
+		return nil
+	}
+	return sample()
+}
+
+// EventFrom converts one of specific event structures (eg. objects.Message) into objects.Event,
+// as it is received in webhooks.
+func EventFrom(event interface{}) (objects.Event, error) {
+	var e objects.Event
+	raw, err := json.Marshal(event)
+	if err != nil {
+		return e, err
+	}
+	err = json.Unmarshal(raw, &e)
+	return e, err
+}
+
+// WriteFixtures writes sample webhook envelope for every supported action into given directory.
+// Files are named after actions, eg. incoming_chat.json.
+func WriteFixtures(dir string, licenseID int, secretKey string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	for _, action := range Actions() {
+		raw, err := Envelope(action, licenseID, secretKey, SamplePayload(action))
+		if err != nil {
+			return err
+		}
+		var fixture bytes.Buffer
+		if err := json.Indent(&fixture, raw, "", "\t"); err != nil {
+			return err
+		}
+		fixture.WriteByte('\n')
+		if err := ioutil.WriteFile(filepath.Join(dir, action+".json"), fixture.Bytes(), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Result represents handler's response to a webhook.
+type Result struct {
+	StatusCode int
+	Body       string
+}
+
+// The TestingT interface is implemented by *testing.T and *testing.B.
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}
+
+// Simulator sends webhooks of single license to given http.Handler.
+type Simulator struct {
+	handler   http.Handler
+	licenseID int
+	secretKey string
+}
+
+// NewSimulator creates Simulator that sends webhooks from given license, signed with given secret key.
+func NewSimulator(h http.Handler, licenseID int, secretKey string) *Simulator {
+	return &Simulator{
+		handler:   h,
+		licenseID: licenseID,
+		secretKey: secretKey,
+	}
+}
+
+// Send builds webhook with given action and payload and passes it to the handler.
+// See Envelope for details of payload requirements.
+func (s *Simulator) Send(action string, payload interface{}) (*Result, error) {
+	raw, err := Envelope(action, s.licenseID, s.secretKey, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	req := httptest.NewRequest("POST", "https://example.com/webhooks", bytes.NewBuffer(raw))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	s.handler.ServeHTTP(resp, req)
+
+	return &Result{
+		StatusCode: resp.Code,
+		Body:       resp.Body.String(),
+	}, nil
+}
+
+// Expect sends webhook the same way as Send does and reports an error
+// if handler's response code is different than statusCode.
+func (s *Simulator) Expect(t TestingT, action string, payload interface{}, statusCode int) *Result {
+	t.Helper()
+	res, err := s.Send(action, payload)
+	if err != nil {
+		t.Errorf("couldn't send %v webhook: %v", action, err)
+		return nil
+	}
+	if res.StatusCode != statusCode {
+		t.Errorf("invalid response code for %v webhook: %v, expected: %v (body: %v)", action, res.StatusCode, statusCode, res.Body)
+	}
+	return res
+}
+
+// ExpectOK sends webhook and reports an error if handler didn't respond with 200 OK.
+func (s *Simulator) ExpectOK(t TestingT, action string, payload interface{}) *Result {
+	t.Helper()
+	return s.Expect(t, action, payload, http.StatusOK)
+}
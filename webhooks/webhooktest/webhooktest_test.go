@@ -0,0 +1,112 @@
+package webhooktest_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/livechat/lc-sdk-go/v2/objects"
+	"github.com/livechat/lc-sdk-go/v2/webhooks"
+	"github.com/livechat/lc-sdk-go/v2/webhooks/webhooktest"
+)
+
+func TestSamplePayloadsAreAcceptedByWebhookHandler(t *testing.T) {
+	cfg := webhooks.NewConfiguration()
+	for _, action := range webhooktest.Actions() {
+		cfg.WithAction(action, func(*webhooks.Webhook) error { return nil }, "secret")
+	}
+	sim := webhooktest.NewSimulator(webhooks.NewWebhookHandler(cfg), 12345, "secret")
+
+	for _, action := range webhooktest.Actions() {
+		sim.ExpectOK(t, action, webhooktest.SamplePayload(action))
+	}
+}
+
+func TestEnvelopeRoundTrip(t *testing.T) {
+	var received *webhooks.IncomingChat
+	cfg := webhooks.NewConfiguration().WithAction("incoming_chat", func(wh *webhooks.Webhook) error {
+		received = wh.Payload.(*webhooks.IncomingChat)
+		if wh.LicenseID != 12345 {
+			t.Errorf("invalid license ID: %v", wh.LicenseID)
+		}
+		return nil
+	}, "")
+	sim := webhooktest.NewSimulator(webhooks.NewWebhookHandler(cfg), 12345, "secret")
+
+	sent := webhooktest.SamplePayload("incoming_chat").(*webhooks.IncomingChat)
+	sim.ExpectOK(t, "incoming_chat", sent)
+
+	if received == nil {
+		t.Fatalf("payload not received")
+	}
+	if received.Chat.ID != sent.Chat.ID {
+		t.Errorf("invalid chat ID: %v", received.Chat.ID)
+	}
+	if len(received.Chat.Threads) != 1 || received.Chat.Threads[0].ID != sent.Chat.Thread.ID {
+		t.Errorf("invalid threads: %v", received.Chat.Threads)
+	}
+	if len(received.Chat.Agents) != 1 || len(received.Chat.Customers) != 1 {
+		t.Errorf("invalid users: %v", received.Chat.Users())
+	}
+}
+
+func TestEventFromMessage(t *testing.T) {
+	e, err := webhooktest.EventFrom(&objects.Message{
+		Event: objects.Event{Type: "message"},
+		Text:  "hello",
+	})
+	if err != nil {
+		t.Fatalf("EventFrom failed: %v", err)
+	}
+	if m := e.Message(); m == nil || m.Text != "hello" {
+		t.Errorf("invalid message: %v", m)
+	}
+}
+
+func TestEnvelopeRejectsMismatchedPayload(t *testing.T) {
+	_, err := webhooktest.Envelope("incoming_chat", 12345, "", &webhooks.IncomingEvent{})
+	if err == nil {
+		t.Errorf("payload of invalid type should be rejected")
+	}
+
+	_, err = webhooktest.Envelope("unknown_action", 12345, "", &webhooks.IncomingEvent{})
+	if err == nil {
+		t.Errorf("unknown action should be rejected")
+	}
+}
+
+func TestExpectReportsHandlerErrors(t *testing.T) {
+	cfg := webhooks.NewConfiguration().WithAction("agent_deleted", func(*webhooks.Webhook) error {
+		return errors.New("failure")
+	}, "")
+	sim := webhooktest.NewSimulator(webhooks.NewWebhookHandler(cfg), 12345, "")
+
+	sim.Expect(t, "agent_deleted", webhooktest.SamplePayload("agent_deleted"), http.StatusInternalServerError)
+}
+
+func TestWriteFixtures(t *testing.T) {
+	dir, err := ioutil.TempDir("", "webhooktest")
+	if err != nil {
+		t.Fatalf("couldn't create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := webhooktest.WriteFixtures(dir, 12345, "secret"); err != nil {
+		t.Fatalf("WriteFixtures failed: %v", err)
+	}
+	for _, action := range webhooktest.Actions() {
+		raw, err := ioutil.ReadFile(filepath.Join(dir, action+".json"))
+		if err != nil {
+			t.Errorf("Missing fixture for action %v", action)
+			continue
+		}
+		var wh webhooks.Webhook
+		if err := json.Unmarshal(raw, &wh); err != nil || wh.Action != action || wh.SecretKey != "secret" {
+			t.Errorf("Invalid fixture for action %v: %s", action, raw)
+		}
+	}
+}